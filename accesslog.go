@@ -0,0 +1,70 @@
+package sdk
+
+import (
+	"net/http"
+	"time"
+)
+
+// The outcomes of a delivery.
+const (
+	outcomeOK            = "ok"
+	outcomeRejected      = "rejected"
+	outcomeRouteNotFound = "route_not_found"
+	outcomeDecodeFailed  = "decode_failed"
+	outcomePanic         = "panic"
+	outcomeHandlerError  = "handler_error"
+	outcomeChaos         = "chaos"
+	outcomeForwarded     = "forwarded"
+	outcomeThrottled     = "throttled"
+	outcomeQueued        = "queued"
+	outcomeDeadLettered  = "dead_lettered"
+)
+
+// Defines what happened to a delivery.
+type delivery struct {
+	route       string
+	jobID       string
+	payloadSize int
+	verified    bool
+	status      int
+	outcome     string
+}
+
+// Writes the error to the response and records it on the delivery.
+func (d *delivery) fail(w http.ResponseWriter, msg string, status int, outcome string) {
+	http.Error(w, msg, status)
+	d.status = status
+	d.outcome = outcome
+}
+
+// WithAccessLog is used to log one record per delivery to the logger specified. Each record
+// has the route, job_id, outcome, status, duration_ms, payload_bytes, and verified keys.
+// Successful, forwarded, and queued deliveries are logged at the info level and everything
+// else at the warn level.
+func WithAccessLog(l Logger) ServerOption {
+	return func(s *Server) {
+		s.accessLog = l
+	}
+}
+
+// Logs the delivery to the access log if one is set.
+func (s *Server) logAccess(d delivery, duration time.Duration) {
+	if s.accessLog == nil {
+		return
+	}
+	log := s.accessLog.Warn
+	switch d.outcome {
+	case outcomeOK, outcomeForwarded, outcomeQueued:
+		log = s.accessLog.Info
+	}
+	log(
+		"clocktick delivery",
+		"route", d.route,
+		"job_id", d.jobID,
+		"outcome", d.outcome,
+		"status", d.status,
+		"duration_ms", float64(duration)/float64(time.Millisecond),
+		"payload_bytes", d.payloadSize,
+		"verified", d.verified,
+	)
+}
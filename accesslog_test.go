@@ -0,0 +1,96 @@
+package sdk_test
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"go.clocktick.dev/sdk"
+)
+
+// Records the level and outcome of each access log record, and the statuses logged.
+type accessLogger struct {
+	mu       sync.Mutex
+	records  []string
+	statuses []int
+}
+
+func (l *accessLogger) record(level string, keysAndValues []any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		switch keysAndValues[i] {
+		case "outcome":
+			l.records = append(l.records, level+" "+keysAndValues[i+1].(string))
+		case "status":
+			l.statuses = append(l.statuses, keysAndValues[i+1].(int))
+		}
+	}
+}
+
+func (l *accessLogger) Debug(_ string, keysAndValues ...any) { l.record("debug", keysAndValues) }
+func (l *accessLogger) Info(_ string, keysAndValues ...any)  { l.record("info", keysAndValues) }
+func (l *accessLogger) Warn(_ string, keysAndValues ...any)  { l.record("warn", keysAndValues) }
+func (l *accessLogger) Error(_ string, keysAndValues ...any) { l.record("error", keysAndValues) }
+
+func (l *accessLogger) last() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.records) == 0 {
+		return ""
+	}
+	return l.records[len(l.records)-1]
+}
+
+func TestAccessLogLevels(t *testing.T) {
+	api := newRecordingAPI(t)
+	l := &accessLogger{}
+	s, signer := newTestServer(t, api.URL, sdk.WithAccessLog(l))
+	addRecordingRoute(s, "email", nil)
+
+	serve(s, signedDelivery(t, signer, time.Now()))
+	if got := l.last(); got != "info ok" {
+		t.Errorf("successful delivery logged as %q, want info ok", got)
+	}
+
+	req := signedDelivery(t, signer, time.Now())
+	req.Header.Set("X-Signature-Timestamp", "1")
+	serve(s, req)
+	if got := l.last(); got != "warn rejected" {
+		t.Errorf("rejected delivery logged as %q, want warn rejected", got)
+	}
+}
+
+func TestAccessLogQueuedDeliveriesAtInfo(t *testing.T) {
+	api := newRecordingAPI(t)
+	l := &accessLogger{}
+	s, signer := newTestServer(
+		t, api.URL, sdk.WithAccessLog(l), sdk.WithAsyncExecution(1, 1, sdk.AsyncHooks{}),
+	)
+	addRecordingRoute(s, "email", nil)
+
+	if w := serve(s, signedDelivery(t, signer, time.Now())); w.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want 202", w.Code)
+	}
+	if got := l.last(); got != "info queued" {
+		t.Errorf("queued delivery logged as %q, want info queued", got)
+	}
+}
+
+func TestAccessLogForwardedStatus(t *testing.T) {
+	api := newRecordingAPI(t)
+	l := &accessLogger{}
+	s, signer := newTestServer(t, api.URL, sdk.WithAccessLog(l))
+
+	// The fallback does not write anything, so the response is an implicit 200.
+	s.SetFallbackForwarder(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	if w := serve(s, signedDelivery(t, signer, time.Now())); w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.statuses) != 1 || l.statuses[0] != http.StatusOK {
+		t.Errorf("logged statuses %v, want [200]", l.statuses)
+	}
+}
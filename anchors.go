@@ -0,0 +1,88 @@
+package sdk
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+// Gets the first instant at or after the wall clock time specified in the location. When the
+// wall clock time falls in a DST gap, time.Date can resolve to an instant before it, so we
+// step forwards by the difference until the wall clock time has been reached.
+func localWallTime(year int, month time.Month, day, hour, min int, loc *time.Location) time.Time {
+	want := time.Date(year, month, day, hour, min, 0, 0, time.UTC)
+	t := time.Date(year, month, day, hour, min, 0, 0, loc)
+	for i := 0; i < 4; i++ {
+		got := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, time.UTC)
+		if !got.Before(want) {
+			break
+		}
+		t = t.Add(want.Sub(got))
+	}
+	return t
+}
+
+// Gets the next time after now that the wall clock in the location reads hour:min.
+func nextLocalTime(now time.Time, hour, min int, loc *time.Location) time.Time {
+	now = now.In(loc)
+	t := localWallTime(now.Year(), now.Month(), now.Day(), hour, min, loc)
+	if !t.After(now) {
+		t = localWallTime(now.Year(), now.Month(), now.Day()+1, hour, min, loc)
+	}
+	return t
+}
+
+// Defines a wall clock time in a location which the builder is resolved against when the job
+// is built, so that it is the next occurrence at that point rather than when the builder was
+// created.
+type localAnchor struct {
+	hour, min int
+	loc       *time.Location
+}
+
+// Resolves the wall clock time of the properties against the time specified if they have one.
+func resolveLocal(props ScheduleJobPropertiesBuilder, now time.Time) ScheduleJobPropertiesBuilder {
+	if p, ok := props.(FromTimePropertiesBuilder); ok && p.local != nil {
+		p.t = nextLocalTime(now, p.local.hour, p.local.min, p.local.loc)
+		return p
+	}
+	return props
+}
+
+// Gets the IANA name of the local timezone, since time.Local is only called "Local". It is
+// taken from the TZ variable or the /etc/localtime link, falling back to "Local" if neither
+// names a zone.
+func localZoneName() string {
+	name, ok := os.LookupEnv("TZ")
+	if ok && name == "" {
+		return "UTC"
+	}
+	if !ok {
+		name, _ = os.Readlink("/etc/localtime")
+	}
+	name = strings.TrimPrefix(name, ":")
+	if i := strings.LastIndex(name, "zoneinfo/"); i != -1 {
+		name = name[i+len("zoneinfo/"):]
+	}
+	if name == "" || strings.HasPrefix(name, "/") {
+		return "Local"
+	}
+	return name
+}
+
+// AtLocalTime is used to create a builder for scheduling a job at the next time the wall
+// clock in the location specified reads hour:min. DST transitions are accounted for; if the
+// time does not exist on that day, the job runs once the clocks have gone forward. The next
+// time is worked out again from the server clock when the job is scheduled. The job recurs
+// in the location, see FromTimePropertiesBuilder.Timezone.
+func AtLocalTime(hour, min int, loc *time.Location) FromTimePropertiesBuilder {
+	p := FromTime(nextLocalTime(time.Now(), hour, min, loc)).InLocation(loc)
+	p.local = &localAnchor{hour: hour, min: min, loc: loc}
+	return p
+}
+
+// NextLocalMidnight is used to create a builder for scheduling a job at the start of the next
+// day in the location specified.
+func NextLocalMidnight(loc *time.Location) FromTimePropertiesBuilder {
+	return AtLocalTime(0, 0, loc)
+}
@@ -0,0 +1,69 @@
+package sdk_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"go.clocktick.dev/sdk"
+)
+
+func TestAtLocalTimeUsesServerClock(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := newRecordingAPI(t)
+
+	// The clocks go forward on 31 March 2024, so 09:00 is 08:00 UTC.
+	now := time.Date(2024, 3, 30, 12, 0, 0, 0, time.UTC)
+	s, _ := newTestServer(t, api.URL, sdk.WithClock(func() time.Time { return now }))
+	r, _ := addRecordingRoute(s, "report", nil)
+
+	props := sdk.AtLocalTime(9, 0, london).EveryDays(1)
+	if _, err := r.Schedule(context.Background(), props, "x"); err != nil {
+		t.Fatal(err)
+	}
+	body := api.last(t).json(t)
+	start := body["start_from"].(map[string]any)
+	if got := start["datetime"]; got != "2024-03-31T08:00:00Z" {
+		t.Errorf("datetime = %v, want 2024-03-31T08:00:00Z", got)
+	}
+	if got := body["timezone"]; got != "Europe/London" {
+		t.Errorf("timezone = %v, want Europe/London", got)
+	}
+}
+
+func TestAtLocalTimeInLocalZone(t *testing.T) {
+	t.Setenv("TZ", "Europe/Paris")
+	api := newRecordingAPI(t)
+	s, _ := newTestServer(t, api.URL)
+	r, _ := addRecordingRoute(s, "report", nil)
+
+	if _, err := r.Schedule(context.Background(), sdk.AtLocalTime(9, 0, time.Local), "x"); err != nil {
+		t.Fatal(err)
+	}
+	if got := api.last(t).json(t)["timezone"]; got != "Europe/Paris" {
+		t.Errorf("timezone = %v, want Europe/Paris", got)
+	}
+}
+
+func TestNextLocalMidnightSkipsToTomorrow(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := newRecordingAPI(t)
+	now := time.Date(2024, 6, 1, 15, 0, 0, 0, time.UTC) // 00:00 on 2 June in Tokyo.
+	s, _ := newTestServer(t, api.URL, sdk.WithClock(func() time.Time { return now }))
+	r, _ := addRecordingRoute(s, "report", nil)
+
+	if _, err := r.Schedule(context.Background(), sdk.NextLocalMidnight(tokyo), "x"); err != nil {
+		t.Fatal(err)
+	}
+	start := api.last(t).json(t)["start_from"].(map[string]any)
+	if got := start["datetime"]; got != "2024-06-02T15:00:00Z" {
+		t.Errorf("datetime = %v, want 2024-06-02T15:00:00Z", got)
+	}
+}
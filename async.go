@@ -0,0 +1,214 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// AsyncHooks is used to be told when a job run by the worker pool finishes. Either hook can
+// be nil.
+type AsyncHooks struct {
+	// OnComplete is called when the handler returns without an error. The result of the
+	// handler, if any, is in the Result field of the invocation.
+	OnComplete func(ctx context.Context, inv *Invocation)
+
+	// OnFailure is called when the handler returns an error or panics. The job is not
+	// re-delivered since it was already acknowledged, so this is where to record or retry it.
+	OnFailure func(ctx context.Context, inv *Invocation, err error)
+}
+
+// A job waiting for a worker.
+type asyncJob struct {
+	ctx     context.Context
+	trace   map[string]string
+	next    []workflowStep
+	route   funcOpts
+	inv     *Invocation
+	release func()
+}
+
+// Defines the worker pool jobs are run on when async execution is on.
+type asyncPool struct {
+	hooks AsyncHooks
+	queue chan asyncJob
+	wg    sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// WithAsyncExecution is used to acknowledge deliveries with a 202 as soon as they are
+// verified and decoded, running the handler afterwards on a pool of workers. This stops slow
+// jobs from tying up inbound connections or hitting the delivery timeout, at the cost of the
+// platform no longer re-delivering jobs which fail. Up to queueSize jobs wait for a worker,
+// and deliveries arriving when the queue is full are rejected with a 503 so that they are
+// re-delivered. Call Drain before shutting down so that queued jobs are not lost.
+func WithAsyncExecution(workers, queueSize int, hooks AsyncHooks) ServerOption {
+	if workers < 1 {
+		panic("workers must be at least 1")
+	}
+	if queueSize < 0 {
+		panic("queueSize must not be negative")
+	}
+	return func(s *Server) {
+		p := &asyncPool{hooks: hooks, queue: make(chan asyncJob, queueSize)}
+		p.wg.Add(workers)
+		for i := 0; i < workers; i++ {
+			go s.asyncWorker(p)
+		}
+		s.async = p
+	}
+}
+
+// Adds the job to the queue without waiting. Returns false if the queue is full or closed.
+func (p *asyncPool) enqueue(j asyncJob) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return false
+	}
+	select {
+	case p.queue <- j:
+		return true
+	default:
+		return false
+	}
+}
+
+// Drain is used to stop accepting deliveries into the worker pool and wait for the jobs
+// already accepted to finish. Deliveries arriving afterwards are rejected with a 503. Returns
+// the error of the context if it is done first. Does nothing if async execution is off.
+func (s *Server) Drain(ctx context.Context) error {
+	p := s.async
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.queue)
+	}
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// A context which keeps the values of its parent but is never cancelled, so that jobs can
+// outlive the request they were delivered in.
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+func (c detachedContext) Value(key any) any         { return c.parent.Value(key) }
+func (c detachedContext) String() string            { return fmt.Sprint(c.parent) + ".detached" }
+
+// Queues the job, acknowledging the delivery with a 202 or rejecting it if the queue is full.
+// The slot of the concurrency limit is released when the job finishes.
+func (s *Server) queueJob(
+	ctx context.Context, w http.ResponseWriter, d *delivery, env payloadEnvelope,
+	route funcOpts, inv *Invocation, release func(),
+) {
+	// Check the arguments decode first since the platform cannot be told once it is queued.
+	if route.check != nil {
+		if err := route.check(inv.Args); err != nil {
+			if release != nil {
+				release()
+			}
+			s.logger.Warn("failed to decode argument", "route", inv.Route, "error", err)
+			s.handleError(ctx, inv.Route, err)
+			d.fail(w, "failed to decode argument", http.StatusBadRequest, outcomeDecodeFailed)
+			return
+		}
+	}
+
+	j := asyncJob{
+		ctx: detachedContext{ctx}, trace: env.Trace, next: env.Next, route: route, inv: inv,
+		release: release,
+	}
+	if !s.async.enqueue(j) {
+		if release != nil {
+			release()
+		}
+		s.logger.Warn("worker pool is full", "route", inv.Route, "job_id", inv.JobID)
+		d.fail(w, "worker pool is full", http.StatusServiceUnavailable, outcomeThrottled)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+	d.status = http.StatusAccepted
+	d.outcome = outcomeQueued
+}
+
+// Runs jobs from the queue until it is closed.
+func (s *Server) asyncWorker(p *asyncPool) {
+	defer p.wg.Done()
+	for j := range p.queue {
+		s.runAsync(p, j)
+	}
+}
+
+// Runs the job and calls the hook for its outcome.
+func (s *Server) runAsync(p *asyncPool, j asyncJob) {
+	if j.release != nil {
+		defer j.release()
+	}
+	panicedValue, err := s.execute(j.ctx, j.trace, j.route, j.inv)
+	if panicedValue != nil {
+		err = fmt.Errorf("panic: %v", panicedValue)
+	} else if err != nil {
+		s.logger.Error(
+			"handler returned an error", "route", j.inv.Route, "job_id", j.inv.JobID, "error", err,
+		)
+		s.handleError(j.ctx, j.inv.Route, err)
+		s.reportError(j.ctx, j.inv.Route, err)
+	}
+
+	// Schedule the next step of the workflow. Since the job cannot be re-delivered, a failure
+	// to do so fails the job.
+	if err == nil && len(j.next) != 0 {
+		if err = s.continueWorkflow(j.ctx, j.inv.JobID, j.next); err != nil {
+			err = fmt.Errorf("failed to schedule next workflow step: %w", err)
+			s.logger.Error(
+				"failed to schedule next workflow step",
+				"route", j.inv.Route, "job_id", j.inv.JobID, "error", err,
+			)
+			s.handleError(j.ctx, j.inv.Route, err)
+		}
+	}
+
+	// Failed jobs are never re-delivered, so they are dead-lettered straight away.
+	if err != nil {
+		s.jobFailed(j.ctx, j.inv, err, true)
+	}
+
+	// Call the hook, making sure a panic in it does not kill the worker.
+	var hook func()
+	switch {
+	case err != nil && p.hooks.OnFailure != nil:
+		hook = func() { p.hooks.OnFailure(j.ctx, j.inv, err) }
+	case err == nil && p.hooks.OnComplete != nil:
+		hook = func() { p.hooks.OnComplete(j.ctx, j.inv) }
+	default:
+		return
+	}
+	if v := panicCondom(hook); v != nil {
+		s.logger.Error(
+			"async hook panicked", "route", j.inv.Route, "job_id", j.inv.JobID, "panic", v,
+		)
+	}
+}
@@ -0,0 +1,89 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// AuditFilter is used to filter the jobs checked by AuditPayloads.
+type AuditFilter struct {
+	// Route limits the audit to jobs for this route.
+	Route string
+
+	// EndpointID limits the audit to jobs for this endpoint.
+	EndpointID string
+}
+
+// AuditIssue defines a job which is expected to fail on delivery.
+type AuditIssue struct {
+	JobID string
+	Route string
+	Err   error
+}
+
+// Wrapped by the error returned from openPayload when the payload cannot be decrypted.
+var errDecryptFailed = errors.New("failed to decrypt data")
+
+// Opens the encrypted payload.
+func (s *Server) openPayload(ctx context.Context, encryptedData string) (payloadEnvelope, error) {
+	decryptedData, err := s.encryptor.Decrypt(ctx, encryptedData)
+	if err != nil {
+		return payloadEnvelope{}, fmt.Errorf("%w: %w", errDecryptFailed, err)
+	}
+	env, err := decodePayload(decryptedData)
+	if err != nil {
+		return payloadEnvelope{}, fmt.Errorf("failed to unmarshal encrypted data: %w", err)
+	}
+	if env, err = s.fetchPayload(ctx, env); err != nil {
+		return payloadEnvelope{}, err
+	}
+	return s.decompressPayload(env)
+}
+
+// Checks that the job would be accepted by the server on delivery.
+func (s *Server) auditJob(ctx context.Context, job Job) error {
+	route, ok := s.funcMap[job.Route]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrRouteNotFound, job.Route)
+	}
+	env, err := s.openPayload(ctx, job.EncryptedData)
+	if err != nil {
+		return err
+	}
+	route, raws, err := s.resolveVersion(job.Route, route, env)
+	if err != nil {
+		return err
+	}
+	raws, ok = route.fitArgs(raws)
+	if !ok {
+		return argumentMismatch(route.numArgs, len(raws))
+	}
+	if route.check != nil {
+		return route.check(raws)
+	}
+	return nil
+}
+
+// AuditPayloads is used to fetch the jobs matching the filter and check that their payloads
+// decrypt and decode against the routes currently registered, without running anything.
+// The jobs which would fail on delivery are returned.
+func (s *Server) AuditPayloads(ctx context.Context, filter AuditFilter) ([]AuditIssue, error) {
+	opts := ListJobsOptions{Route: filter.Route, EndpointID: filter.EndpointID}
+	var issues []AuditIssue
+	for {
+		list, err := s.ListJobs(ctx, opts)
+		if err != nil {
+			return issues, err
+		}
+		for _, job := range list.Jobs {
+			if err := s.auditJob(ctx, job); err != nil {
+				issues = append(issues, AuditIssue{JobID: job.ID, Route: job.Route, Err: err})
+			}
+		}
+		if list.NextCursor == "" {
+			return issues, nil
+		}
+		opts.Cursor = list.NextCursor
+	}
+}
@@ -0,0 +1,147 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// RetryPolicy is used to configure how API requests are retried after transient failures,
+// which are network errors and 502, 503, and 504 responses. Requests are only retried when
+// repeating them is safe, so job creation is only retried when the job has a custom ID or an
+// idempotency key, see WithIdempotencyKeys. Zero values use the defaults.
+type RetryPolicy struct {
+	// MaxAttempts is the most times a request is sent, including the first. Set this to 1 to
+	// disable retries. Defaults to 3.
+	MaxAttempts int
+
+	// InitialBackoff is how long to wait before the first retry. This doubles after each
+	// retry. Defaults to 200ms.
+	InitialBackoff time.Duration
+
+	// MaxBackoff is the longest to wait between attempts. Defaults to 5s.
+	MaxBackoff time.Duration
+
+	// MaxRateLimitWait is the longest to wait for a rate limit to reset before retrying.
+	// Rate limited requests are always safe to retry since the API did not process them. If
+	// this is zero, or the wait would be longer, a RateLimitError is returned instead.
+	MaxRateLimitWait time.Duration
+}
+
+// WithRetryPolicy is used to set how the server retries API requests.
+func WithRetryPolicy(p RetryPolicy) ServerOption {
+	return func(s *Server) {
+		s.Client.retry = p
+	}
+}
+
+// WithClientRetryPolicy is used to set how the client retries API requests.
+func WithClientRetryPolicy(p RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retry = p
+	}
+}
+
+// Gets the number of attempts with the default applied.
+func (p RetryPolicy) maxAttempts() int {
+	if p.MaxAttempts <= 0 {
+		return 3
+	}
+	return p.MaxAttempts
+}
+
+// Gets how long to wait before the retry specified, with jitter applied. retry is 0 for the
+// first retry.
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	initial, ceiling := p.InitialBackoff, p.MaxBackoff
+	if initial <= 0 {
+		initial = 200 * time.Millisecond
+	}
+	if ceiling <= 0 {
+		ceiling = 5 * time.Second
+	}
+	// Shift the ceiling down rather than the initial backoff up, which can overflow.
+	d := ceiling
+	if initial < ceiling>>retry {
+		d = initial << retry
+	}
+
+	// Wait somewhere between half and all of the backoff so clients don't retry in lockstep.
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// Gets how long to wait before retrying the request which failed with the error, returning
+// false if it should not be retried. retry is 0 for the first retry.
+func (p RetryPolicy) retryDelay(
+	ctx context.Context, idempotent bool, err error, retry int,
+) (time.Duration, bool) {
+	if ctx.Err() != nil {
+		return 0, false
+	}
+	var rlErr RateLimitError
+	if errors.As(err, &rlErr) {
+		wait := rlErr.RetryAfter()
+		if wait == 0 {
+			wait = p.backoff(retry)
+		}
+		return wait, p.MaxRateLimitWait > 0 && wait <= p.MaxRateLimitWait
+	}
+	if !idempotent || !retryableError(err) {
+		return 0, false
+	}
+	return p.backoff(retry), true
+}
+
+// Checks if the error from a request is transient.
+func retryableError(err error) bool {
+	var reqErr RequestError
+	if errors.As(err, &reqErr) {
+		switch reqErr.Status {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+
+	// The HTTP client returns a *url.Error when the request could not be sent.
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// Calls f until it succeeds, fails with an error which is not transient, or runs out of
+// attempts. onRetry is called before each retry.
+func (p RetryPolicy) do(ctx context.Context, idempotent bool, onRetry func(), f func() error) error {
+	attempts := p.maxAttempts()
+	for attempt := 1; ; attempt++ {
+		err := f()
+		if err == nil || attempt >= attempts {
+			return err
+		}
+		wait, ok := p.retryDelay(ctx, idempotent, err, attempt-1)
+		if !ok {
+			return err
+		}
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return err
+		case <-t.C:
+		}
+		if onRetry != nil {
+			onRetry()
+		}
+	}
+}
+
+// Checks if requests with the method can be repeated safely.
+func idempotentMethod(method string) bool {
+	switch method {
+	case "GET", "HEAD", "PUT", "DELETE", "OPTIONS":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+)
+
+// WithBaggageKeys is used to declare context keys whose values are carried from the context
+// passed to ScheduleJob into the context of the handler when the job is delivered. Only
+// string values are propagated and they travel inside the encrypted payload. Keys are
+// matched between processes by their type name and value, so they must be declared in the
+// same way on both sides.
+func WithBaggageKeys(keys ...any) ServerOption {
+	return func(s *Server) {
+		s.baggageKeys = append(s.baggageKeys, keys...)
+	}
+}
+
+// Gets the name a baggage key is carried under.
+func baggageKeyName(key any) string {
+	return fmt.Sprintf("%T:%v", key, key)
+}
+
+// Collects the baggage from the context.
+func (s *Server) collectBaggage(ctx context.Context) map[string]string {
+	var baggage map[string]string
+	for _, key := range s.baggageKeys {
+		v, ok := ctx.Value(key).(string)
+		if !ok {
+			continue
+		}
+		if baggage == nil {
+			baggage = make(map[string]string, len(s.baggageKeys))
+		}
+		baggage[baggageKeyName(key)] = v
+	}
+	return baggage
+}
+
+// Restores the baggage into the context.
+func (s *Server) restoreBaggage(ctx context.Context, baggage map[string]string) context.Context {
+	if len(baggage) == 0 {
+		return ctx
+	}
+	for _, key := range s.baggageKeys {
+		if v, ok := baggage[baggageKeyName(key)]; ok {
+			ctx = context.WithValue(ctx, key, v)
+		}
+	}
+	return ctx
+}
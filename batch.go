@@ -0,0 +1,209 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"runtime"
+	"sync"
+)
+
+// JobSpec defines a job to be scheduled with ScheduleJobs.
+type JobSpec struct {
+	Route string
+	Props ScheduleJobPropertiesBuilder
+	Args  []any
+}
+
+// JobResult defines the result of scheduling a single job with ScheduleJobs.
+type JobResult struct {
+	Response JobCreationResponse
+	Err      error
+}
+
+// WithBatchWorkers is used to set how many jobs ScheduleJobs encodes and encrypts at once.
+// Defaults to GOMAXPROCS.
+func WithBatchWorkers(n int) ServerOption {
+	return func(s *Server) {
+		s.batchWorkers = n
+	}
+}
+
+// WithBatchSize is used to set the maximum number of jobs ScheduleJobs sends to the API in a
+// single request. Defaults to 500.
+func WithBatchSize(n int) ServerOption {
+	return func(s *Server) {
+		s.batchSize = n
+	}
+}
+
+type preparedJob struct {
+	id   string
+	body createJobSkeleton
+	err  error
+}
+
+// Prepares the jobs across a bounded pool of workers.
+func (s *Server) prepareJobs(ctx context.Context, specs []JobSpec) []preparedJob {
+	workers := s.batchWorkers
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(specs) {
+		workers = len(specs)
+	}
+
+	prepared := make([]preparedJob, len(specs))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				spec := specs[i]
+				p := &prepared[i]
+				p.id, p.body, p.err = s.prepareJob(ctx, spec.Route, spec.Props, spec.Args, nil)
+			}
+		}()
+	}
+	for i := range specs {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return prepared
+}
+
+const batchJobsPath = "/batch/jobs"
+
+type batchJob struct {
+	CustomID       string `json:"custom_id,omitempty"`
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+	createJobSkeleton
+}
+
+type batchRequest struct {
+	Jobs []batchJob `json:"jobs"`
+}
+
+type batchResponse struct {
+	Results []struct {
+		JobID string    `json:"job_id"`
+		Error *APIError `json:"error"`
+	} `json:"results"`
+}
+
+// Sends a chunk of prepared jobs to the batch endpoint, filling in the results at the indexes
+// specified.
+func (s *Server) submitBatch(
+	ctx context.Context, prepared []preparedJob, indexes []int, results []JobResult,
+) {
+	// The batch is only retried if every job has a custom ID or an idempotency key, since the
+	// API will then not create any of them twice.
+	req := batchRequest{Jobs: make([]batchJob, len(indexes))}
+	keyed := true
+	for i, index := range indexes {
+		p := prepared[index]
+		req.Jobs[i] = batchJob{
+			CustomID: p.id, IdempotencyKey: p.body.idempotencyKey, createJobSkeleton: p.body,
+		}
+		keyed = keyed && (p.id != "" || p.body.idempotencyKey != "")
+	}
+	s.incr(counterScheduleCalls)
+	var resp batchResponse
+	err := s.sendRetrying(ctx, keyed, "POST", s.baseURL+batchJobsPath, nil, req, &resp)
+	if batchUnsupported(err) {
+		// Fall back to sending the jobs one at a time.
+		for _, index := range indexes {
+			results[index].Response, results[index].Err = s.submitJob(
+				ctx, prepared[index].id, prepared[index].body,
+			)
+		}
+		return
+	}
+	if err == nil && len(resp.Results) != len(indexes) {
+		err = errors.New("batch response has the wrong number of results")
+	}
+	for i, index := range indexes {
+		switch {
+		case err != nil:
+			results[index].Err = err
+		case resp.Results[i].Error != nil:
+			results[index].Err = conflictError(*resp.Results[i].Error)
+		default:
+			results[index].Response.JobID = resp.Results[i].JobID
+		}
+	}
+}
+
+// Checks if the error is from an API without the batch endpoint.
+func batchUnsupported(err error) bool {
+	var reqErr RequestError
+	if errors.As(err, &reqErr) {
+		return reqErr.Status == http.StatusNotFound || reqErr.Status == http.StatusMethodNotAllowed
+	}
+	return false
+}
+
+// ScheduleJobs is used to schedule many jobs at once. The payloads are encoded and encrypted
+// concurrently and then sent to the API in batches (see WithBatchSize). The results are in
+// the same order as the specs and a failure of one job does not stop the others from being
+// scheduled.
+func (s *Server) ScheduleJobs(ctx context.Context, specs []JobSpec) []JobResult {
+	if s.local != nil {
+		results := make([]JobResult, len(specs))
+		for i, spec := range specs {
+			results[i].Response, results[i].Err = s.scheduleLocal(ctx, spec.Route, spec.Props, spec.Args, nil)
+		}
+		return results
+	}
+
+	batchSize := s.batchSize
+	if batchSize < 1 {
+		batchSize = 500
+	}
+
+	results := make([]JobResult, len(specs))
+	prepared := s.prepareJobs(ctx, specs)
+	indexes := make([]int, 0, batchSize)
+	for i, p := range prepared {
+		if p.err != nil {
+			results[i].Err = p.err
+			continue
+		}
+		indexes = append(indexes, i)
+		if len(indexes) == batchSize {
+			s.submitBatch(ctx, prepared, indexes, results)
+			indexes = indexes[:0]
+		}
+	}
+	if len(indexes) != 0 {
+		s.submitBatch(ctx, prepared, indexes, results)
+	}
+	return results
+}
+
+// ScheduleFanOut is used to schedule one job against the route for each set of arguments,
+// all with the same schedule, such as a job per customer. It is ScheduleJobs under the hood,
+// so the jobs are sent in batches and the results are in the same order as the argument
+// sets, with a failure of one job not stopping the others. Since every job shares the
+// properties, they must not have a custom ID or idempotency key.
+func (s *Server) ScheduleFanOut(
+	ctx context.Context, route string, props ScheduleJobPropertiesBuilder, argSets [][]any,
+) []JobResult {
+	id, body := props.buildSkeleton()
+	if (id != "" || body.idempotencyKey != "") && len(argSets) > 1 {
+		err := errors.New("fan out properties must not have a custom ID or idempotency key")
+		results := make([]JobResult, len(argSets))
+		for i := range results {
+			results[i].Err = err
+		}
+		return results
+	}
+	specs := make([]JobSpec, len(argSets))
+	for i, args := range argSets {
+		specs[i] = JobSpec{Route: route, Props: props, Args: args}
+	}
+	return s.ScheduleJobs(ctx, specs)
+}
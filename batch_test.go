@@ -0,0 +1,243 @@
+package sdk_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.clocktick.dev/sdk"
+	"go.clocktick.dev/sdk/sdktest"
+)
+
+// Gets the jobs of a batch request.
+func batchJobs(t *testing.T, req recordedRequest) []map[string]any {
+	t.Helper()
+	if req.Method != "POST" || req.Path != "/batch/jobs" {
+		t.Fatalf("got %s %s, want POST /batch/jobs", req.Method, req.Path)
+	}
+	var body struct {
+		Jobs []map[string]any `json:"jobs"`
+	}
+	if err := json.Unmarshal(req.Body, &body); err != nil {
+		t.Fatal(err)
+	}
+	return body.Jobs
+}
+
+// Replies to a batch request with a created job for each job in it.
+func batchReply(req recordedRequest) (int, string) {
+	var body struct {
+		Jobs []json.RawMessage `json:"jobs"`
+	}
+	_ = json.Unmarshal(req.Body, &body)
+	results := make([]string, len(body.Jobs))
+	for i := range results {
+		results[i] = `{"job_id":"job_` + string(rune('a'+i)) + `"}`
+	}
+	return 200, `{"results":[` + strings.Join(results, ",") + `]}`
+}
+
+func TestScheduleJobsSendsIdempotencyKeys(t *testing.T) {
+	api := newRecordingAPI(t)
+	api.setReply(batchReply)
+	s, _ := newTestServer(t, api.URL, sdk.WithIdempotencyKeys())
+	addRecordingRoute(s, "email", nil)
+
+	results := s.ScheduleJobs(context.Background(), []sdk.JobSpec{
+		{Route: "email", Props: sdk.FromNow().Minutes(1).IdempotencyKey("user-key"), Args: []any{"a"}},
+		{Route: "email", Props: sdk.FromNow().Minutes(1), Args: []any{"b"}},
+		{Route: "email", Props: sdk.FromNow().Minutes(1), Args: []any{"c"}},
+	})
+	for i, res := range results {
+		if res.Err != nil {
+			t.Fatalf("job %d: %v", i, res.Err)
+		}
+	}
+
+	jobs := batchJobs(t, api.last(t))
+	if len(jobs) != 3 {
+		t.Fatalf("got %d jobs, want 3", len(jobs))
+	}
+	if got := jobs[0]["idempotency_key"]; got != "user-key" {
+		t.Errorf("job 0 idempotency_key = %v, want user-key", got)
+	}
+	k1, _ := jobs[1]["idempotency_key"].(string)
+	k2, _ := jobs[2]["idempotency_key"].(string)
+	if k1 == "" || k2 == "" || k1 == k2 {
+		t.Errorf("generated keys = %q and %q, want two different keys", k1, k2)
+	}
+}
+
+func TestScheduleJobsIsIdempotentAgainstFakeAPI(t *testing.T) {
+	api := sdktest.NewAPI("")
+	defer api.Close()
+	pub, _, err := sdktest.GenerateKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newTestServerWithKey(t, api.URL, pub)
+	addRecordingRoute(s, "email", nil)
+
+	specs := []sdk.JobSpec{
+		{Route: "email", Props: sdk.FromNow().Minutes(1).IdempotencyKey("k1"), Args: []any{"a"}},
+		{Route: "email", Props: sdk.FromNow().Minutes(1).IdempotencyKey("k2"), Args: []any{"b"}},
+	}
+	first := s.ScheduleJobs(context.Background(), specs)
+	second := s.ScheduleJobs(context.Background(), specs)
+	for i := range specs {
+		if first[i].Err != nil || second[i].Err != nil {
+			t.Fatalf("job %d: %v, %v", i, first[i].Err, second[i].Err)
+		}
+		if first[i].Response.JobID != second[i].Response.JobID {
+			t.Errorf("job %d: got %q then %q", i, first[i].Response.JobID, second[i].Response.JobID)
+		}
+	}
+	if n := len(api.Jobs()); n != 2 {
+		t.Errorf("API has %d jobs, want 2", n)
+	}
+}
+
+func TestBufferedSchedulingSendsIdempotencyKeys(t *testing.T) {
+	api := newRecordingAPI(t)
+	api.setReply(batchReply)
+	s, _ := newTestServer(
+		t, api.URL, sdk.WithIdempotencyKeys(),
+		sdk.WithBufferedScheduling(10, 10, time.Hour, nil),
+	)
+	r, _ := addRecordingRoute(s, "email", nil)
+
+	if _, err := r.Schedule(context.Background(), sdk.FromNow().Minutes(1), "a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	jobs := batchJobs(t, api.last(t))
+	if len(jobs) != 1 {
+		t.Fatalf("got %d jobs, want 1", len(jobs))
+	}
+	if key, _ := jobs[0]["idempotency_key"].(string); key == "" {
+		t.Error("buffered job was sent without an idempotency key")
+	}
+}
+
+// Replies to the first batch request with a 503 and to the rest with created jobs.
+func failFirstBatch() func(recordedRequest) (int, string) {
+	var calls atomic.Int32
+	return func(req recordedRequest) (int, string) {
+		if calls.Add(1) == 1 {
+			return http.StatusServiceUnavailable, `{}`
+		}
+		return batchReply(req)
+	}
+}
+
+// The retry policy used by tests which retry quickly.
+var fastRetries = sdk.WithRetryPolicy(sdk.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond})
+
+func TestScheduleJobsRetriesKeyedBatch(t *testing.T) {
+	api := newRecordingAPI(t)
+	api.setReply(failFirstBatch())
+	s, _ := newTestServer(t, api.URL, sdk.WithIdempotencyKeys(), fastRetries)
+	addRecordingRoute(s, "email", nil)
+
+	results := s.ScheduleJobs(context.Background(), []sdk.JobSpec{
+		{Route: "email", Props: sdk.FromNow().Minutes(1), Args: []any{"a"}},
+		{Route: "email", Props: sdk.FromNow().Minutes(1).CustomID("b"), Args: []any{"b"}},
+	})
+	for i, res := range results {
+		if res.Err != nil {
+			t.Errorf("job %d: %v", i, res.Err)
+		}
+	}
+	if n := len(api.received()); n != 2 {
+		t.Errorf("API received %d requests, want 2", n)
+	}
+}
+
+func TestScheduleJobsDoesNotRetryUnkeyedBatch(t *testing.T) {
+	api := newRecordingAPI(t)
+	api.setReply(failFirstBatch())
+	s, _ := newTestServer(t, api.URL, fastRetries)
+	addRecordingRoute(s, "email", nil)
+
+	results := s.ScheduleJobs(context.Background(), []sdk.JobSpec{
+		{Route: "email", Props: sdk.FromNow().Minutes(1), Args: []any{"a"}},
+		{Route: "email", Props: sdk.FromNow().Minutes(1), Args: []any{"b"}},
+	})
+	for i, res := range results {
+		if res.Err == nil {
+			t.Errorf("job %d: no error", i)
+		}
+	}
+	if n := len(api.received()); n != 1 {
+		t.Errorf("API received %d requests, want 1", n)
+	}
+}
+
+func TestBufferedSchedulingRetriesBatches(t *testing.T) {
+	api := newRecordingAPI(t)
+	api.setReply(failFirstBatch())
+	var failures []error
+	s, _ := newTestServer(
+		t, api.URL, fastRetries,
+		sdk.WithBufferedScheduling(10, 10, time.Hour, func(_, _ string, err error) {
+			failures = append(failures, err)
+		}),
+	)
+	r, _ := addRecordingRoute(s, "email", nil)
+
+	if _, err := r.Schedule(context.Background(), sdk.FromNow().Minutes(1), "a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(failures) != 0 {
+		t.Errorf("got failures %v", failures)
+	}
+	if n := len(api.received()); n != 2 {
+		t.Errorf("API received %d requests, want 2", n)
+	}
+}
+
+func TestCloseFlushesBufferedJobs(t *testing.T) {
+	api := newRecordingAPI(t)
+	api.setReply(batchReply)
+	s, _ := newTestServer(t, api.URL, sdk.WithBufferedScheduling(10, 10, time.Hour, nil))
+	r, _ := addRecordingRoute(s, "email", nil)
+	ctx := context.Background()
+
+	for _, name := range []string{"a", "b"} {
+		if _, err := r.Schedule(ctx, sdk.FromNow().Minutes(1), name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := len(api.received()); n != 0 {
+		t.Fatalf("API received %d requests before Close, want 0", n)
+	}
+	if err := s.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if jobs := batchJobs(t, api.last(t)); len(jobs) != 2 {
+		t.Errorf("got %d jobs, want 2", len(jobs))
+	}
+	if _, err := r.Schedule(ctx, sdk.FromNow().Minutes(1), "c"); err == nil {
+		t.Error("job was buffered after Close")
+	}
+	if err := s.Close(ctx); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+}
+
+func TestCloseWithoutBufferedScheduling(t *testing.T) {
+	api := newRecordingAPI(t)
+	s, _ := newTestServer(t, api.URL)
+	if err := s.Close(context.Background()); err != nil {
+		t.Error(err)
+	}
+}
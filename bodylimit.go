@@ -0,0 +1,55 @@
+package sdk
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// The default maximum size of a delivery body.
+const defaultMaxBodySize = 4 << 20
+
+// BodyTooLargeError is used when a delivery body is larger than the limit set with
+// WithMaxBodySize. The delivery is rejected with a 413 Request Entity Too Large.
+type BodyTooLargeError struct {
+	// Limit is the maximum body size in bytes.
+	Limit int64
+}
+
+// Error is used to convert the body too large error to a string.
+func (e *BodyTooLargeError) Error() string {
+	return "body is larger than " + strconv.FormatInt(e.Limit, 10) + " bytes"
+}
+
+// WithMaxBodySize is used to set the maximum size in bytes of a delivery body. Larger bodies
+// are rejected with a 413 before they are verified. Defaults to 4MB. Panics if n is not
+// positive.
+func WithMaxBodySize(n int64) ServerOption {
+	if n <= 0 {
+		panic("max body size must be positive")
+	}
+	return func(s *Server) {
+		s.maxBodySize = n
+	}
+}
+
+// Reads the delivery body, returning a BodyTooLargeError if it is over the limit.
+func (s *Server) readBody(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	limit := s.maxBodySize
+	if limit <= 0 {
+		limit = defaultMaxBodySize
+	}
+	if r.ContentLength > limit {
+		return nil, &BodyTooLargeError{Limit: limit}
+	}
+	b, err := io.ReadAll(http.MaxBytesReader(w, r.Body, limit))
+	if err != nil {
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			return nil, &BodyTooLargeError{Limit: limit}
+		}
+		return nil, err
+	}
+	return b, nil
+}
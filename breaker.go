@@ -0,0 +1,108 @@
+package sdk
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned by API calls without sending a request when the circuit breaker
+// is open because the API has been failing.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitBreaker is used to configure the circuit breaker set with WithCircuitBreaker. Zero
+// values use the defaults.
+type CircuitBreaker struct {
+	// FailureThreshold is how many transient failures in a row open the circuit, which are
+	// network errors and 502, 503, and 504 responses. Defaults to 5.
+	FailureThreshold int
+
+	// Cooldown is how long the circuit stays open before a single request is let through to
+	// check if the API has recovered. If it succeeds, the circuit closes, and if it fails, the
+	// circuit opens again straight away. Other requests fail fast until then. Defaults to 30s.
+	Cooldown time.Duration
+}
+
+// Tracks the failures of requests to decide if the circuit is open.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+
+	// Set whilst the request let through after the cooldown is in flight.
+	probing bool
+}
+
+// WithCircuitBreaker is used to make API calls fail fast with ErrCircuitOpen when the API
+// keeps failing, rather than every call waiting on its timeout whilst the API is down.
+func WithCircuitBreaker(cb CircuitBreaker) ServerOption {
+	return func(s *Server) {
+		WithClientCircuitBreaker(cb)(s.Client)
+	}
+}
+
+// WithClientCircuitBreaker is used to make API calls made by the client fail fast with
+// ErrCircuitOpen when the API keeps failing.
+func WithClientCircuitBreaker(cb CircuitBreaker) ClientOption {
+	if cb.FailureThreshold < 0 || cb.Cooldown < 0 {
+		panic("circuit breaker settings must not be negative")
+	}
+	return func(c *Client) {
+		b := &circuitBreaker{threshold: cb.FailureThreshold, cooldown: cb.Cooldown}
+		if b.threshold == 0 {
+			b.threshold = 5
+		}
+		if b.cooldown == 0 {
+			b.cooldown = 30 * time.Second
+		}
+		c.breaker = b
+	}
+}
+
+// Checks if a request can be sent. Once the cooldown has passed, a single request is let
+// through as a probe until its outcome is recorded.
+func (b *circuitBreaker) allow() (ok, probe bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.threshold {
+		return true, false
+	}
+	if b.probing || time.Now().Before(b.openUntil) {
+		return false, false
+	}
+	b.probing = true
+	return true, true
+}
+
+// Records the outcome of a request, opening the circuit if it has failed too many times.
+// probe is if the request was let through as the probe by allow.
+func (b *circuitBreaker) record(err error, probe bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if probe {
+		b.probing = false
+	} else if b.failures >= b.threshold {
+		// Whilst the circuit is open, only the probe decides if it closes. Requests sent
+		// before it opened which finish late are ignored.
+		return
+	}
+	if err == nil || !retryableError(err) {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openUntil = time.Now().Add(b.cooldown)
+	}
+}
+
+// Lets another probe through after the probe ended without an outcome, such as when it was
+// cancelled by the caller.
+func (b *circuitBreaker) abandon() {
+	b.mu.Lock()
+	b.probing = false
+	b.mu.Unlock()
+}
@@ -0,0 +1,189 @@
+package sdk_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.clocktick.dev/sdk"
+)
+
+// Creates a server with a circuit breaker which opens after two failures, and without retries.
+func newBreakerServer(t *testing.T, api *recordingAPI) *sdk.Server {
+	t.Helper()
+	s, _ := newTestServer(
+		t, api.URL,
+		sdk.WithRetryPolicy(sdk.RetryPolicy{MaxAttempts: 1}),
+		sdk.WithCircuitBreaker(sdk.CircuitBreaker{FailureThreshold: 2, Cooldown: 50 * time.Millisecond}),
+	)
+	return s
+}
+
+// Replies with the status specified.
+func replyStatus(status *atomic.Int32) func(recordedRequest) (int, string) {
+	return func(recordedRequest) (int, string) {
+		return int(status.Load()), `{"id":"job_1"}`
+	}
+}
+
+func TestCircuitBreakerOpensAfterFailures(t *testing.T) {
+	api := newRecordingAPI(t)
+	var status atomic.Int32
+	status.Store(http.StatusServiceUnavailable)
+	api.setReply(replyStatus(&status))
+	s := newBreakerServer(t, api)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := s.GetJob(ctx, "job_1"); err == nil || errors.Is(err, sdk.ErrCircuitOpen) {
+			t.Fatalf("request %d: err = %v, want the API error", i, err)
+		}
+	}
+	if _, err := s.GetJob(ctx, "job_1"); !errors.Is(err, sdk.ErrCircuitOpen) {
+		t.Errorf("err = %v, want ErrCircuitOpen", err)
+	}
+	if n := len(api.received()); n != 2 {
+		t.Errorf("API received %d requests, want 2", n)
+	}
+
+	// Once the cooldown passes and the API recovers, the circuit closes again.
+	status.Store(http.StatusOK)
+	time.Sleep(60 * time.Millisecond)
+	for i := 0; i < 2; i++ {
+		if _, err := s.GetJob(ctx, "job_1"); err != nil {
+			t.Fatalf("request %d after cooldown: %v", i, err)
+		}
+	}
+}
+
+func TestCircuitBreakerLetsOneProbeThrough(t *testing.T) {
+	api := newRecordingAPI(t)
+	var status atomic.Int32
+	status.Store(http.StatusServiceUnavailable)
+	api.setReply(replyStatus(&status))
+	s := newBreakerServer(t, api)
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		_, _ = s.GetJob(ctx, "job_1")
+	}
+	time.Sleep(60 * time.Millisecond)
+
+	// Hold the probe in flight whilst other requests are made.
+	release := make(chan struct{})
+	started := make(chan struct{})
+	api.setReply(func(recordedRequest) (int, string) {
+		close(started)
+		<-release
+		return http.StatusOK, `{"id":"job_1"}`
+	})
+	probeErr := make(chan error, 1)
+	go func() {
+		_, err := s.GetJob(ctx, "job_1")
+		probeErr <- err
+	}()
+	<-started
+	for i := 0; i < 3; i++ {
+		if _, err := s.GetJob(ctx, "job_1"); !errors.Is(err, sdk.ErrCircuitOpen) {
+			t.Errorf("request %d during probe: err = %v, want ErrCircuitOpen", i, err)
+		}
+	}
+	close(release)
+	if err := <-probeErr; err != nil {
+		t.Fatalf("probe: %v", err)
+	}
+	if n := len(api.received()); n != 3 {
+		t.Errorf("API received %d requests, want 3", n)
+	}
+}
+
+func TestCircuitBreakerReopensWhenProbeFails(t *testing.T) {
+	api := newRecordingAPI(t)
+	var status atomic.Int32
+	status.Store(http.StatusServiceUnavailable)
+	api.setReply(replyStatus(&status))
+	s := newBreakerServer(t, api)
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		_, _ = s.GetJob(ctx, "job_1")
+	}
+	time.Sleep(60 * time.Millisecond)
+
+	if _, err := s.GetJob(ctx, "job_1"); err == nil || errors.Is(err, sdk.ErrCircuitOpen) {
+		t.Fatalf("probe: err = %v, want the API error", err)
+	}
+	if _, err := s.GetJob(ctx, "job_1"); !errors.Is(err, sdk.ErrCircuitOpen) {
+		t.Errorf("err after failed probe = %v, want ErrCircuitOpen", err)
+	}
+}
+
+func TestCircuitBreakerIgnoresClientErrors(t *testing.T) {
+	api := newRecordingAPI(t)
+	api.setReply(func(recordedRequest) (int, string) { return http.StatusNotFound, `{}` })
+	s := newBreakerServer(t, api)
+	for i := 0; i < 5; i++ {
+		if _, err := s.GetJob(context.Background(), "job_1"); errors.Is(err, sdk.ErrCircuitOpen) {
+			t.Fatalf("request %d: circuit opened on 404s", i)
+		}
+	}
+}
+
+func TestCircuitBreakerIgnoresSlowRequestsDuringProbe(t *testing.T) {
+	api := newRecordingAPI(t)
+	var n atomic.Int32
+	slowStarted, releaseSlow := make(chan struct{}), make(chan struct{})
+	probeStarted, releaseProbe := make(chan struct{}), make(chan struct{})
+	api.setReply(func(recordedRequest) (int, string) {
+		switch n.Add(1) {
+		case 1:
+			close(slowStarted)
+			<-releaseSlow
+		case 2, 3:
+			return http.StatusServiceUnavailable, `{}`
+		case 4:
+			close(probeStarted)
+			<-releaseProbe
+		}
+		return http.StatusOK, `{"id":"job_1"}`
+	})
+	s := newBreakerServer(t, api)
+	ctx := context.Background()
+
+	// A slow request is sent before the circuit opens.
+	slowErr := make(chan error, 1)
+	go func() {
+		_, err := s.GetJob(ctx, "job_1")
+		slowErr <- err
+	}()
+	<-slowStarted
+	for i := 0; i < 2; i++ {
+		_, _ = s.GetJob(ctx, "job_1")
+	}
+	time.Sleep(60 * time.Millisecond)
+
+	probeErr := make(chan error, 1)
+	go func() {
+		_, err := s.GetJob(ctx, "job_1")
+		probeErr <- err
+	}()
+	<-probeStarted
+
+	// The slow request succeeding whilst the probe is in flight does not close the circuit
+	// or let another probe through.
+	close(releaseSlow)
+	if err := <-slowErr; err != nil {
+		t.Fatalf("slow request: %v", err)
+	}
+	if _, err := s.GetJob(ctx, "job_1"); !errors.Is(err, sdk.ErrCircuitOpen) {
+		t.Errorf("err during probe = %v, want ErrCircuitOpen", err)
+	}
+	close(releaseProbe)
+	if err := <-probeErr; err != nil {
+		t.Fatalf("probe: %v", err)
+	}
+	if n := len(api.received()); n != 4 {
+		t.Errorf("API received %d requests, want 4", n)
+	}
+}
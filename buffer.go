@@ -0,0 +1,183 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// A job which has been prepared and is waiting to be sent.
+type bufferedJob struct {
+	route string
+	id    string
+	body  createJobSkeleton
+}
+
+// Holds jobs scheduled whilst buffered scheduling is on until they are sent in batches.
+type scheduleBuffer struct {
+	maxBatch int
+	maxQueue int
+	onError  func(route, customID string, err error)
+	wake     chan struct{}
+	stop     chan struct{}
+	done     chan struct{}
+
+	mu      sync.Mutex
+	pending []bufferedJob
+	closed  bool
+
+	// Held whilst a flush is sending jobs so that flushes happen one at a time.
+	flushMu sync.Mutex
+}
+
+// WithBufferedScheduling is used to make ScheduleJob return as soon as the job is validated
+// and encrypted, queueing it to be sent to the API in batches by a background goroutine. The
+// queue is flushed every interval, or sooner once maxBatch jobs are waiting. Up to maxQueue
+// jobs can wait, and ScheduleJob returns an error once the queue is full. Since the job is
+// not created when ScheduleJob returns, the response only has the job ID if a custom ID was
+// set, and failures are passed to onError, or logged if it is nil. Buffered jobs are always
+// given an idempotency key so that batches can be retried. Call Close when shutting down so
+// that queued jobs are not lost.
+func WithBufferedScheduling(
+	maxBatch, maxQueue int, interval time.Duration, onError func(route, customID string, err error),
+) ServerOption {
+	if maxBatch < 1 || maxQueue < 1 {
+		panic("maxBatch and maxQueue must be at least 1")
+	}
+	if interval <= 0 {
+		panic("interval must be positive")
+	}
+	return func(s *Server) {
+		b := &scheduleBuffer{
+			maxBatch: maxBatch,
+			maxQueue: maxQueue,
+			onError:  onError,
+			wake:     make(chan struct{}, 1),
+			stop:     make(chan struct{}),
+			done:     make(chan struct{}),
+		}
+		s.buffer = b
+		go s.flushLoop(b, interval)
+	}
+}
+
+// Adds the job to the queue, waking the flusher if a batch is ready.
+func (b *scheduleBuffer) add(j bufferedJob) error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return errors.New("scheduling queue is closed")
+	}
+	if len(b.pending) >= b.maxQueue {
+		b.mu.Unlock()
+		return errors.New("scheduling queue is full")
+	}
+	b.pending = append(b.pending, j)
+	full := len(b.pending) >= b.maxBatch
+	b.mu.Unlock()
+	if full {
+		select {
+		case b.wake <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// Flushes the queue every interval or when woken until it is stopped.
+func (s *Server) flushLoop(b *scheduleBuffer, interval time.Duration) {
+	defer close(b.done)
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+		case <-b.wake:
+		case <-b.stop:
+			return
+		}
+		_ = s.flushBuffer(context.Background(), b)
+	}
+}
+
+// Sends the queued jobs in batches, reporting the ones which fail.
+func (s *Server) flushBuffer(ctx context.Context, b *scheduleBuffer) error {
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		b.mu.Lock()
+		n := len(b.pending)
+		if n > b.maxBatch {
+			n = b.maxBatch
+		}
+		jobs := b.pending[:n:n]
+		b.pending = b.pending[n:]
+		b.mu.Unlock()
+		if len(jobs) == 0 {
+			return nil
+		}
+
+		prepared := make([]preparedJob, len(jobs))
+		indexes := make([]int, len(jobs))
+		for i, j := range jobs {
+			prepared[i] = preparedJob{id: j.id, body: j.body}
+			indexes[i] = i
+		}
+		results := make([]JobResult, len(jobs))
+		s.submitBatch(ctx, prepared, indexes, results)
+		for i, res := range results {
+			if res.Err == nil {
+				continue
+			}
+			if b.onError != nil {
+				b.onError(jobs[i].route, jobs[i].id, res.Err)
+			} else {
+				s.logger.Error(
+					"failed to send buffered job", "route", jobs[i].route, "error", res.Err,
+				)
+			}
+		}
+	}
+}
+
+// Flush is used to send the jobs queued by buffered scheduling straight away, returning once
+// they have been sent. Jobs which fail are passed to the error handler as normal. If the
+// context is done first, the jobs not yet sent stay queued and the error of the context is
+// returned. Does nothing if buffered scheduling is off.
+func (s *Server) Flush(ctx context.Context) error {
+	if s.buffer == nil {
+		return nil
+	}
+	return s.flushBuffer(ctx, s.buffer)
+}
+
+// Close is used to shut down the background work of the server before it exits. The worker
+// pool is drained as with Drain, and then the goroutine sending the jobs queued by buffered
+// scheduling is stopped and the jobs still queued are sent. ScheduleJob returns an error for
+// buffered jobs afterwards. Returns the error of the context if it is done first, in which
+// case Flush can be called to send the rest of the jobs.
+func (s *Server) Close(ctx context.Context) error {
+	if err := s.Drain(ctx); err != nil {
+		return err
+	}
+	b := s.buffer
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	if !b.closed {
+		b.closed = true
+		close(b.stop)
+	}
+	b.mu.Unlock()
+	select {
+	case <-b.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return s.flushBuffer(ctx, b)
+}
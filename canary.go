@@ -0,0 +1,19 @@
+package sdk
+
+import "math/rand"
+
+type canary struct {
+	endpointId string
+	percent    float64
+}
+
+// CanaryEndpointID is used to send the percentage of newly scheduled jobs specified (between 0
+// and 100) to another endpoint, so that a new deployment can be validated on some real jobs.
+func CanaryEndpointID(endpointId string, percent float64) Option {
+	return Option{canary: &canary{endpointId: endpointId, percent: percent}}
+}
+
+// Picks if a job should go to the canary endpoint.
+func (c *canary) pick() bool {
+	return rand.Float64()*100 < c.percent
+}
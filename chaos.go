@@ -0,0 +1,82 @@
+//go:build clocktick_chaos
+
+package sdk
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// ChaosConfig is used to configure the faults injected by WithChaos. Rates are between 0 and 1.
+type ChaosConfig struct {
+	// DecryptFailureRate is how often a delivery fails as though it could not be decrypted.
+	DecryptFailureRate float64
+
+	// DelayRate is how often the handler is delayed by Delay before running.
+	DelayRate float64
+	Delay     time.Duration
+
+	// ErrorRate is how often a 503 is returned after the handler has run, which causes the
+	// job to be delivered again.
+	ErrorRate float64
+
+	// DuplicateRate is how often the handler is run twice for the same delivery.
+	DuplicateRate float64
+
+	// Seed is the seed for the random source. If 0, the current time is used.
+	Seed int64
+}
+
+type chaos struct {
+	cfg  ChaosConfig
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// WithChaos is used to randomly inject faults into deliveries so that retry and
+// deduplication handling can be tested. This is for testing only, so it is only built with
+// the clocktick_chaos build tag, such as with go test -tags clocktick_chaos.
+func WithChaos(cfg ChaosConfig) ServerOption {
+	return func(s *Server) {
+		seed := cfg.Seed
+		if seed == 0 {
+			seed = time.Now().UnixNano()
+		}
+		s.chaos = &chaos{cfg: cfg, rand: rand.New(rand.NewSource(seed))}
+	}
+}
+
+// Returns true at the rate specified.
+func (c *chaos) roll(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rand.Float64() < rate
+}
+
+// The methods below are called on a nil *chaos when chaos is not enabled.
+
+// Checks if the delivery should fail as though it could not be decrypted.
+func (c *chaos) failDecrypt() bool {
+	return c != nil && c.roll(c.cfg.DecryptFailureRate)
+}
+
+// Delays the handler if it should be delayed.
+func (c *chaos) delay() {
+	if c != nil && c.roll(c.cfg.DelayRate) {
+		time.Sleep(c.cfg.Delay)
+	}
+}
+
+// Checks if a 503 should be returned after the handler has run.
+func (c *chaos) failResponse() bool {
+	return c != nil && c.roll(c.cfg.ErrorRate)
+}
+
+// Checks if the handler should be run a second time.
+func (c *chaos) duplicate() bool {
+	return c != nil && c.roll(c.cfg.DuplicateRate)
+}
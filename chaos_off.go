@@ -0,0 +1,11 @@
+//go:build !clocktick_chaos
+
+package sdk
+
+// Faults are only injected when built with the clocktick_chaos build tag, see WithChaos.
+type chaos struct{}
+
+func (*chaos) failDecrypt() bool  { return false }
+func (*chaos) delay()             {}
+func (*chaos) failResponse() bool { return false }
+func (*chaos) duplicate() bool    { return false }
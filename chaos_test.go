@@ -0,0 +1,26 @@
+//go:build clocktick_chaos
+
+package sdk_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"go.clocktick.dev/sdk"
+)
+
+func TestChaosInjectsFaults(t *testing.T) {
+	api := newRecordingAPI(t)
+	s, signer := newTestServer(t, api.URL, sdk.WithChaos(sdk.ChaosConfig{
+		ErrorRate: 1, DuplicateRate: 1, Seed: 1,
+	}))
+	_, calls := addRecordingRoute(s, "email", nil)
+
+	if w := serve(s, signedDelivery(t, signer, time.Now())); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", w.Code)
+	}
+	if got := calls(); len(got) != 2 {
+		t.Errorf("handler was called %d times, want 2", len(got))
+	}
+}
@@ -0,0 +1,78 @@
+package sdk
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"fmt"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// Cipher is used to select the algorithm job arguments are encrypted with. Both algorithms
+// are always accepted when decrypting, so the cipher can be changed without breaking jobs
+// which were created before the change.
+type Cipher int
+
+const (
+	// CipherAESGCM is AES-256-GCM. This is the default and is understood by every SDK.
+	CipherAESGCM Cipher = iota
+
+	// CipherXChaCha20Poly1305 is XChaCha20-Poly1305. Its 24 byte nonce makes it safe to
+	// encrypt any number of payloads with random nonces. Make sure every SDK handling the jobs
+	// supports it before switching.
+	CipherXChaCha20Poly1305
+)
+
+// The prefix of the encrypted data when it was encrypted with XChaCha20-Poly1305. Data
+// without a prefix was encrypted with AES-GCM.
+const xchachaPrefix = "xchacha20poly1305"
+
+// WithCipher is used to set the cipher the server encrypts job arguments with.
+func WithCipher(c Cipher) ServerOption {
+	return func(s *Server) {
+		s.Client.cipher = c
+	}
+}
+
+// WithClientCipher is used to set the cipher the client encrypts job arguments with.
+func WithClientCipher(c Cipher) ClientOption {
+	return func(cl *Client) {
+		cl.cipher = c
+	}
+}
+
+// Defines the AEADs for each cipher, all keyed from the same encryption key.
+type ciphers struct {
+	aesgcm  cipher.AEAD
+	xchacha cipher.AEAD
+}
+
+// Creates the AEADs used to encrypt job arguments from the encryption key.
+func newCiphers(encryptionKey string) (*ciphers, error) {
+	// Hash the encryption key with sha256.
+	encryptionKeyHash := sha256.Sum256([]byte(encryptionKey))
+
+	// Turn it into the encryptors.
+	block, err := aes.NewCipher(encryptionKeyHash[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	xchacha, err := chacha20poly1305.NewX(encryptionKeyHash[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return &ciphers{aesgcm: gcm, xchacha: xchacha}, nil
+}
+
+// Gets the AEAD and the prefix of the data for the cipher.
+func (cs *ciphers) get(c Cipher) (cipher.AEAD, string) {
+	if c == CipherXChaCha20Poly1305 {
+		return cs.xchacha, xchachaPrefix
+	}
+	return cs.aesgcm, ""
+}
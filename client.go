@@ -0,0 +1,203 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Client is used to manage jobs without handling deliveries. Unlike a Server, it does not need
+// the public key or any routes, so it suits services which only create and manage jobs. The
+// encryption key is only needed to schedule jobs since their arguments are encrypted.
+type Client struct {
+	client            *http.Client
+	apiKey            string
+	baseURL           string
+	encryptor         Encryptor
+	cipher            Cipher
+	defaultEndpointId string
+	tracer            Tracer
+	retry             RetryPolicy
+	autoIdemKeys      bool
+	random            io.Reader
+	compressor        Compressor
+	compressMinSize   int
+	decompressors     []Compressor
+	payloadStore      PayloadStore
+	offloadMinSize    int
+	breaker           *circuitBreaker
+	headers           http.Header
+	interceptors      []Interceptor
+
+	// Called before a request is retried.
+	onRetry func()
+}
+
+// ClientOption is used to configure a client when it is created.
+type ClientOption func(*Client)
+
+// NewClient is used to create a new client with the API key specified.
+func NewClient(apiKey string, opts ...ClientOption) *Client {
+	c := &Client{
+		client:  DefaultClient(),
+		apiKey:  apiKey,
+		baseURL: DefaultBaseURL,
+		tracer:  nopTracer{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.configureEncryptor()
+	return c
+}
+
+// WithClientHTTPClient is used to set the HTTP client the client makes API requests with.
+func WithClientHTTPClient(client *http.Client) ClientOption {
+	return func(c *Client) {
+		c.client = client
+	}
+}
+
+// WithClientBaseURL is used to set the base URL of the Clocktick API. Defaults to
+// DefaultBaseURL.
+func WithClientBaseURL(baseURL string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithClientEncryptionKey is used to set the encryption key job arguments are encrypted with.
+// This must match the key of the server handling the jobs.
+func WithClientEncryptionKey(encryptionKey string) ClientOption {
+	return func(c *Client) {
+		// sha256 always gives a valid key, so this cannot fail.
+		keys, _ := newCiphers(encryptionKey)
+		c.encryptor = &aeadEncryptor{keys: keys}
+	}
+}
+
+// WithClientEndpointID is used to set the endpoint ID jobs are scheduled against.
+func WithClientEndpointID(endpointId string) ClientOption {
+	return func(c *Client) {
+		c.defaultEndpointId = endpointId
+	}
+}
+
+// Encrypts the payload and fills in the rest of the body.
+func (c *Client) sealJob(
+	ctx context.Context, body createJobSkeleton, route, endpointId string, payload []byte,
+) (createJobSkeleton, error) {
+	encryptedData, err := c.encryptor.Encrypt(ctx, payload)
+	if err != nil {
+		return body, fmt.Errorf("failed to encrypt data: %w", err)
+	}
+	body.EndpointID = endpointId
+	body.EncryptedData = encryptedData
+	body.JobType = route
+	return body, nil
+}
+
+// ScheduleJob is used to schedule a job against the route specified. Since the client has no
+// routes, the arguments are not checked against the handler, so make sure they match what the
+// server expects.
+func (c *Client) ScheduleJob(
+	ctx context.Context, route string, props ScheduleJobPropertiesBuilder,
+	args ...any,
+) (JobCreationResponse, error) {
+	if c.encryptor == nil {
+		return JobCreationResponse{}, errors.New("encryption key is required to schedule jobs")
+	}
+	if c.defaultEndpointId == "" {
+		return JobCreationResponse{}, errors.New("endpoint ID is required to schedule jobs")
+	}
+	props = resolveLocal(props, time.Now())
+	if err := validateProps(props, time.Now(), false); err != nil {
+		return JobCreationResponse{}, err
+	}
+	ctx, span := c.tracer.Start(ctx, "clocktick.schedule", "route", route)
+	res, err := c.scheduleJob(ctx, route, props, args)
+	span.End(err)
+	return res, err
+}
+
+// Encodes, encrypts, and sends the job.
+func (c *Client) scheduleJob(
+	ctx context.Context, route string, props ScheduleJobPropertiesBuilder, args []any,
+) (JobCreationResponse, error) {
+	raws, err := encodeArgs(args)
+	if err != nil {
+		return JobCreationResponse{}, err
+	}
+	b, err := c.packPayload(ctx, payloadEnvelope{Args: raws, Trace: c.traceCarrier(ctx)})
+	if err != nil {
+		return JobCreationResponse{}, err
+	}
+	id, body := props.buildSkeleton()
+	body, err = c.sealJob(ctx, body, route, c.defaultEndpointId, b)
+	if err != nil {
+		return JobCreationResponse{}, err
+	}
+	return c.submitJob(ctx, id, body)
+}
+
+// Sends a request to the API, retrying transient failures if the method is idempotent.
+func (c *Client) send(ctx context.Context, method, reqUrl string, body, respBody any) error {
+	return c.sendRetrying(ctx, idempotentMethod(method), method, reqUrl, nil, body, respBody)
+}
+
+// Sends a request which is safe to repeat regardless of its method.
+func (c *Client) sendIdempotent(
+	ctx context.Context, method, reqUrl string, body, respBody any,
+) error {
+	return c.sendRetrying(ctx, true, method, reqUrl, nil, body, respBody)
+}
+
+// Sends a request to the API within a span, retrying transient failures if it is idempotent.
+func (c *Client) sendRetrying(
+	ctx context.Context, idempotent bool, method, reqUrl string, header http.Header,
+	body, respBody any,
+) error {
+	ctx, span := c.tracer.Start(ctx, "clocktick.request", "method", method, "url", reqUrl)
+	header = c.requestHeader(header)
+	send := func() error {
+		return sendRequest(
+			ctx, c.client, c.apiKey, reqUrl, method, header, body, respBody, c.interceptors,
+		)
+	}
+	err := c.retry.do(ctx, idempotent, c.onRetry, func() error {
+		if c.breaker == nil {
+			return send()
+		}
+		ok, probe := c.breaker.allow()
+		if !ok {
+			return ErrCircuitOpen
+		}
+		err := send()
+		switch {
+		case ctx.Err() == nil:
+			c.breaker.record(err, probe)
+		case probe:
+			// Requests cancelled by the caller say nothing about the API, so another probe
+			// is let through.
+			c.breaker.abandon()
+		}
+		return err
+	})
+	span.End(err)
+	return err
+}
+
+// DeleteJob is used to delete the job with the ID specified. This is also available on the
+// server, which embeds the client.
+func (c *Client) DeleteJob(ctx context.Context, jobId string) error {
+	if jobId == "" {
+		return errors.New("job ID is required")
+	}
+	reqUrl := c.baseURL + jobsPath + "/" + url.PathEscape(jobId)
+	return c.send(ctx, "DELETE", reqUrl, nil, nil)
+}
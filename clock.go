@@ -0,0 +1,47 @@
+package sdk
+
+import "time"
+
+// WithClock is used to set the function the server gets the current time from. When set,
+// FromNow schedules are resolved against this clock rather than the time the API receives
+// them, so that tests and backfills are deterministic.
+func WithClock(now func() time.Time) ServerOption {
+	return func(s *Server) {
+		s.clock = now
+	}
+}
+
+// Gets the current time from the server clock.
+func (s *Server) now() time.Time {
+	if s.clock != nil {
+		return s.clock()
+	}
+	return time.Now()
+}
+
+// Implemented by builders which are relative to the current time.
+type anchorable interface {
+	anchorTo(t time.Time) ScheduleJobPropertiesBuilder
+}
+
+// Anchors the properties to the server clock. Relative schedules are only anchored if a clock
+// is set, since the API otherwise resolves them when it receives them, whereas local wall
+// clock times are always resolved.
+func (s *Server) anchorProps(props ScheduleJobPropertiesBuilder) ScheduleJobPropertiesBuilder {
+	if s.clock != nil {
+		if a, ok := props.(anchorable); ok {
+			props = a.anchorTo(s.clock())
+		}
+	}
+	return resolveLocal(props, s.now())
+}
+
+// Adds the delta to the time specified.
+func (d Delta) addTo(t time.Time) time.Time {
+	return t.AddDate(int(d.Years), int(d.Months), int(d.Days)).Add(
+		time.Duration(d.Hours)*time.Hour +
+			time.Duration(d.Minutes)*time.Minute +
+			time.Duration(d.Seconds)*time.Second +
+			time.Duration(d.Milliseconds)*time.Millisecond,
+	)
+}
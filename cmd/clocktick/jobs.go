@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"go.clocktick.dev/sdk"
+)
+
+// Schedules a job with the route and JSON arguments specified.
+func schedule(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("schedule", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: clocktick schedule [flags] <route> [json args...]")
+		fs.PrintDefaults()
+	}
+	in := fs.Duration("in", 0, "run the job after this duration")
+	recurring := fs.Bool("recurring", false, "run the job every -in duration")
+	at := fs.String("at", "", "run the job at this RFC 3339 time")
+	cron := fs.String("cron", "", "run the job on this cron expression")
+	tz := fs.String("tz", "", "the IANA timezone of -at")
+	id := fs.String("id", "", "the custom ID of the job")
+	_ = fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	jobArgs, err := parseArgs(fs.Args()[1:])
+	if err != nil {
+		return err
+	}
+
+	// Build the properties from whichever schedule flag was set.
+	var props sdk.ScheduleJobPropertiesBuilder
+	switch {
+	case *in > 0 && *at == "" && *cron == "":
+		p := sdk.FromDuration(*in).CustomID(*id)
+		if *recurring {
+			p = p.Recurring()
+		}
+		props = p
+	case *at != "" && *in == 0 && *cron == "":
+		t, err := time.Parse(time.RFC3339, *at)
+		if err != nil {
+			return fmt.Errorf("-at is not a valid RFC 3339 time: %w", err)
+		}
+		p := sdk.FromTime(t).CustomID(*id)
+		if *tz != "" {
+			p = p.Timezone(*tz)
+		}
+		props = p
+	case *cron != "" && *in == 0 && *at == "":
+		props = sdk.FromCron(*cron).CustomID(*id)
+	default:
+		return errors.New("exactly one of -in, -at, or -cron is required")
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+	res, err := client.ScheduleJob(ctx, fs.Arg(0), props, jobArgs...)
+	if err != nil {
+		return err
+	}
+	fmt.Println(res.JobID)
+	return nil
+}
+
+// Lists the jobs matching the filters, following the cursor until the limit is reached.
+func list(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	route := fs.String("route", "", "only list jobs for this route")
+	endpoint := fs.String("endpoint", "", "only list jobs for this endpoint ID")
+	status := fs.String("status", "", "only list jobs with this status")
+	tag := fs.String("tag", "", "only list jobs with this tag")
+	limit := fs.Int("limit", 100, "the maximum number of jobs to list, or 0 for all")
+	asJSON := fs.Bool("json", false, "print the jobs as JSON")
+	_ = fs.Parse(args)
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+	opts := sdk.ListJobsOptions{
+		Route: *route, EndpointID: *endpoint, Status: sdk.JobStatus(*status), Tag: *tag,
+	}
+	var jobs []sdk.Job
+	for {
+		page, err := client.ListJobs(ctx, opts)
+		if err != nil {
+			return err
+		}
+		jobs = append(jobs, page.Jobs...)
+		if page.NextCursor == "" || (*limit > 0 && len(jobs) >= *limit) {
+			break
+		}
+		opts.Cursor = page.NextCursor
+	}
+	if *limit > 0 && len(jobs) > *limit {
+		jobs = jobs[:*limit]
+	}
+
+	if *asJSON {
+		return printJSON(jobs)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tROUTE\tSTATUS\tNEXT RUN")
+	for _, job := range jobs {
+		next := "-"
+		if job.NextRunAt != nil {
+			next = job.NextRunAt.Local().Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", job.ID, job.Route, job.Status, next)
+	}
+	return w.Flush()
+}
+
+// Prints the job with the ID specified.
+func get(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: clocktick get <job id>")
+	}
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+	job, err := client.GetJob(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	return printJSON(job)
+}
+
+// Deletes the jobs with the IDs specified.
+func deleteJob(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: clocktick delete <job id>...")
+	}
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+	for _, id := range args {
+		if err := client.DeleteJob(ctx, id); err != nil {
+			return fmt.Errorf("failed to delete %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// Prints the delivery attempts of the job with the ID specified, newest first.
+func runs(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("runs", flag.ExitOnError)
+	limit := fs.Int("limit", 20, "the maximum number of runs to show")
+	asJSON := fs.Bool("json", false, "print the runs as JSON")
+	_ = fs.Parse(args)
+	if fs.NArg() != 1 {
+		return errors.New("usage: clocktick runs [flags] <job id>")
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+	list, err := client.JobRuns(ctx, fs.Arg(0), sdk.JobRunsOptions{Limit: *limit})
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		return printJSON(list.Runs)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STARTED\tSTATUS\tDURATION\tERROR")
+	for _, run := range list.Runs {
+		fmt.Fprintf(
+			w, "%s\t%d\t%s\t%s\n",
+			run.StartedAt.Local().Format(time.RFC3339), run.StatusCode, run.Duration, run.Error,
+		)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,127 @@
+// Command clocktick is used to manage Clocktick jobs from the terminal and to send signed
+// deliveries to a running handler for smoke testing.
+//
+// The API key, encryption key, endpoint ID, and base URL are read from the CLOCKTICK_API_KEY,
+// CLOCKTICK_ENCRYPTION_KEY, CLOCKTICK_ENDPOINT_ID, and CLOCKTICK_BASE_URL environment
+// variables. The trigger subcommand reads the hex encoded Ed25519 private key from
+// CLOCKTICK_PRIVATE_KEY.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"go.clocktick.dev/sdk"
+)
+
+const usage = `Usage: clocktick <command> [flags]
+
+Commands:
+  schedule  Schedule a job
+  list      List jobs
+  get       Show a job
+  delete    Delete a job
+  runs      Show the delivery attempts of a job
+  trigger   Send a signed delivery to a running handler
+
+Run "clocktick <command> -h" for the flags of a command.
+`
+
+// Defines a subcommand.
+type command func(ctx context.Context, args []string) error
+
+var commands = map[string]command{
+	"schedule": schedule,
+	"list":     list,
+	"get":      get,
+	"delete":   deleteJob,
+	"runs":     runs,
+	"trigger":  trigger,
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	err := cmd(ctx, os.Args[2:])
+	stop()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+// Creates the client from the environment.
+func newClient() (*sdk.Client, error) {
+	apiKey := os.Getenv("CLOCKTICK_API_KEY")
+	if apiKey == "" {
+		return nil, errors.New("CLOCKTICK_API_KEY is not set")
+	}
+	var opts []sdk.ClientOption
+	if key := os.Getenv("CLOCKTICK_ENCRYPTION_KEY"); key != "" {
+		opts = append(opts, sdk.WithClientEncryptionKey(key))
+	}
+	if id := os.Getenv("CLOCKTICK_ENDPOINT_ID"); id != "" {
+		opts = append(opts, sdk.WithClientEndpointID(id))
+	}
+	if u := os.Getenv("CLOCKTICK_BASE_URL"); u != "" {
+		opts = append(opts, sdk.WithClientBaseURL(u))
+	}
+	return sdk.NewClient(apiKey, opts...), nil
+}
+
+// Parses the arguments of a job from JSON. Whole numbers are decoded as integers so that they
+// can be passed to handlers taking integer arguments.
+func parseArgs(raw []string) ([]any, error) {
+	args := make([]any, len(raw))
+	for i, s := range raw {
+		dec := json.NewDecoder(strings.NewReader(s))
+		dec.UseNumber()
+		if err := dec.Decode(&args[i]); err != nil {
+			return nil, fmt.Errorf("argument %d is not valid JSON: %w", i+1, err)
+		}
+		args[i] = convertNumbers(args[i])
+	}
+	return args, nil
+}
+
+// Converts the JSON numbers within the value to integers or floats.
+func convertNumbers(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	case []any:
+		for i := range v {
+			v[i] = convertNumbers(v[i])
+		}
+	case map[string]any:
+		for k := range v {
+			v[k] = convertNumbers(v[k])
+		}
+	}
+	return v
+}
+
+// Prints the value as indented JSON.
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
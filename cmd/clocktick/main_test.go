@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.clocktick.dev/sdk"
+	"go.clocktick.dev/sdk/sdktest"
+)
+
+const testEncryptionKey = "test-encryption-key"
+
+// Runs the command, returning what it printed to stdout.
+func run(t *testing.T, cmd command, args ...string) (string, error) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	out := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(r)
+		out <- string(b)
+	}()
+	err = cmd(context.Background(), args)
+	_ = w.Close()
+	return <-out, err
+}
+
+// Starts a fake API which is closed when the test ends, and points the environment at it.
+func setupAPI(t *testing.T) *sdktest.API {
+	t.Helper()
+	api := sdktest.NewAPI("api-key")
+	t.Cleanup(api.Close)
+	t.Setenv("CLOCKTICK_API_KEY", "api-key")
+	t.Setenv("CLOCKTICK_ENCRYPTION_KEY", testEncryptionKey)
+	t.Setenv("CLOCKTICK_ENDPOINT_ID", "endpoint")
+	t.Setenv("CLOCKTICK_BASE_URL", api.URL)
+	return api
+}
+
+func TestParseArgs(t *testing.T) {
+	got, err := parseArgs([]string{`"ada"`, "3", "1.5", `[1,{"n":2}]`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []any{"ada", int64(3), 1.5, []any{int64(1), map[string]any{"n": int64(2)}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v, want %#v", got, want)
+	}
+	if _, err := parseArgs([]string{"ada"}); err == nil {
+		t.Error("argument which is not JSON was accepted")
+	}
+}
+
+func TestDecodePrivateKey(t *testing.T) {
+	_, priv, err := sdktest.GenerateKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{hex.EncodeToString(priv.Seed()), hex.EncodeToString(priv)} {
+		got, err := decodePrivateKey(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(priv) {
+			t.Error("decoded key does not match")
+		}
+	}
+	for _, s := range []string{"", "zz", "abcd"} {
+		if _, err := decodePrivateKey(s); err == nil {
+			t.Errorf("%q was accepted", s)
+		}
+	}
+}
+
+func TestScheduleAndManageJobs(t *testing.T) {
+	api := setupAPI(t)
+
+	out, err := run(t, schedule, "-cron", "0 9 * * 1", "-id", "weekly", "greet", `"ada"`, "3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := strings.TrimSpace(out)
+	job, ok := api.Job(id)
+	if !ok || job.CustomID != "weekly" || job.Route != "greet" {
+		t.Fatalf("got job %+v for %q", job, id)
+	}
+
+	out, err = run(t, list, "-json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var jobs []sdk.Job
+	if err := json.Unmarshal([]byte(out), &jobs); err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 1 || jobs[0].ID != id {
+		t.Errorf("listed %+v", jobs)
+	}
+	if out, err = run(t, get, id); err != nil || !strings.Contains(out, `"weekly"`) {
+		t.Errorf("get printed %s, err %v", out, err)
+	}
+
+	if _, err := run(t, deleteJob, id); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := api.Job(id); ok {
+		t.Error("job was not deleted")
+	}
+	if _, err := run(t, deleteJob, id); err == nil {
+		t.Error("deleting a missing job returned no error")
+	}
+}
+
+func TestScheduleRequiresOneSchedule(t *testing.T) {
+	setupAPI(t)
+	for _, args := range [][]string{
+		{"greet"},
+		{"-in", "1m", "-cron", "0 9 * * 1", "greet"},
+		{"-at", "tomorrow", "greet"},
+	} {
+		if _, err := run(t, schedule, args...); err == nil {
+			t.Errorf("%v was accepted", args)
+		}
+	}
+}
+
+func TestCommandsRequireAPIKey(t *testing.T) {
+	setupAPI(t)
+	t.Setenv("CLOCKTICK_API_KEY", "")
+	if _, err := run(t, list); err == nil || !strings.Contains(err.Error(), "CLOCKTICK_API_KEY") {
+		t.Errorf("err = %v, want CLOCKTICK_API_KEY to be required", err)
+	}
+}
+
+func TestTrigger(t *testing.T) {
+	pub, priv, err := sdktest.GenerateKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := sdk.NewServerE("api-key", testEncryptionKey, pub, "endpoint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []any
+	sdk.AddRoute2(s, "greet", func(_ context.Context, name string, n int) error {
+		got = append(got, name, n)
+		return nil
+	})
+	handler := httptest.NewServer(s)
+	defer handler.Close()
+	t.Setenv("CLOCKTICK_ENCRYPTION_KEY", testEncryptionKey)
+	t.Setenv("CLOCKTICK_PRIVATE_KEY", hex.EncodeToString(priv.Seed()))
+
+	for _, flags := range [][]string{nil, {"-xchacha"}} {
+		got = nil
+		args := append(flags, handler.URL, "greet", `"ada"`, "3")
+		if out, err := run(t, trigger, args...); err != nil {
+			t.Fatalf("%v: %v: %s", flags, err, out)
+		}
+		if !reflect.DeepEqual(got, []any{"ada", 3}) {
+			t.Errorf("%v: handler got %v, want [ada 3]", flags, got)
+		}
+	}
+	if _, err := run(t, trigger, handler.URL, "missing"); err == nil {
+		t.Error("delivery to a missing route returned no error")
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"go.clocktick.dev/sdk"
+	"go.clocktick.dev/sdk/sdktest"
+)
+
+// Decodes the private key from hex, accepting either the seed or the full private key.
+func decodePrivateKey(s string) (ed25519.PrivateKey, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("private key is not valid hex: %w", err)
+	}
+	switch len(b) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(b), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(b), nil
+	default:
+		return nil, fmt.Errorf("private key must be %d or %d bytes, got %d",
+			ed25519.SeedSize, ed25519.PrivateKeySize, len(b))
+	}
+}
+
+// Sends a signed and encrypted delivery to the handler at the URL, printing the response.
+func trigger(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("trigger", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: clocktick trigger [flags] <url> <route> [json args...]")
+		fs.PrintDefaults()
+	}
+	jobID := fs.String("job-id", "", "the job ID of the delivery, defaults to a generated one")
+	xchacha := fs.Bool("xchacha", false, "encrypt the delivery with XChaCha20-Poly1305")
+	_ = fs.Parse(args)
+	if fs.NArg() < 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	jobArgs, err := parseArgs(fs.Args()[2:])
+	if err != nil {
+		return err
+	}
+
+	// Get the keys from the environment.
+	privateKey, err := decodePrivateKey(os.Getenv("CLOCKTICK_PRIVATE_KEY"))
+	if err != nil {
+		return fmt.Errorf("CLOCKTICK_PRIVATE_KEY: %w", err)
+	}
+	encryptionKey := os.Getenv("CLOCKTICK_ENCRYPTION_KEY")
+	if encryptionKey == "" {
+		return errors.New("CLOCKTICK_ENCRYPTION_KEY is not set")
+	}
+	var opts []sdktest.SignerOption
+	if *xchacha {
+		opts = append(opts, sdktest.WithCipher(sdk.CipherXChaCha20Poly1305))
+	}
+	signer, err := sdktest.NewSigner(privateKey, encryptionKey, opts...)
+	if err != nil {
+		return err
+	}
+
+	// Build and send the delivery.
+	id := *jobID
+	if id == "" {
+		id = "cli-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	body, err := signer.Body(fs.Arg(1), id, jobArgs...)
+	if err != nil {
+		return err
+	}
+	req, err := signer.Sign(fs.Arg(0), body, time.Now())
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	fmt.Println(resp.Status)
+	if _, err = io.Copy(os.Stdout, resp.Body); err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("handler returned %s", resp.Status)
+	}
+	return nil
+}
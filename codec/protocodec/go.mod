@@ -0,0 +1,17 @@
+module go.clocktick.dev/sdk/codec/protocodec
+
+go 1.20
+
+require (
+	github.com/vmihailenco/msgpack/v5 v5.4.1
+	go.clocktick.dev/sdk v0.0.0
+	google.golang.org/protobuf v1.33.0
+)
+
+require (
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+	golang.org/x/crypto v0.21.0 // indirect
+	golang.org/x/sys v0.18.0 // indirect
+)
+
+replace go.clocktick.dev/sdk => ../..
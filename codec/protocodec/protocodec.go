@@ -0,0 +1,47 @@
+// Package protocodec is used to send protobuf messages as job arguments of the Clocktick SDK.
+// Registered message types are written in the protobuf wire format inside the encrypted
+// payload instead of being encoded field by field with msgpack, so that they keep the
+// compatibility rules of their schema.
+package protocodec
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+// Register is used to register the message type with the msgpack extension ID specified, so
+// that job arguments of that type are encoded with protobuf. msg is used only for its type and
+// is usually a nil pointer such as (*pb.Report)(nil). Every message type needs its own ID
+// between 0 and 127, and the services scheduling and handling the jobs must register the same
+// types with the same IDs. Call this during init before any jobs are scheduled or delivered.
+func Register(extID int8, msg proto.Message) {
+	if extID < 0 {
+		panic("extID must be between 0 and 127")
+	}
+	typ := reflect.TypeOf(msg)
+	if typ == nil || typ.Kind() != reflect.Ptr {
+		panic("msg must be a pointer to a message type")
+	}
+
+	msgpack.RegisterExtEncoder(extID, msg, func(_ *msgpack.Encoder, v reflect.Value) ([]byte, error) {
+		m, ok := v.Interface().(proto.Message)
+		if !ok {
+			return nil, fmt.Errorf("protocodec: %s is not a proto.Message", v.Type())
+		}
+		return proto.Marshal(m)
+	})
+	msgpack.RegisterExtDecoder(extID, msg, func(dec *msgpack.Decoder, v reflect.Value, extLen int) error {
+		b := make([]byte, extLen)
+		if err := dec.ReadFull(b); err != nil {
+			return err
+		}
+		m, ok := v.Interface().(proto.Message)
+		if !ok {
+			return fmt.Errorf("protocodec: %s is not a proto.Message", v.Type())
+		}
+		return proto.Unmarshal(b, m)
+	})
+}
@@ -0,0 +1,75 @@
+package protocodec_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"go.clocktick.dev/sdk"
+	"go.clocktick.dev/sdk/codec/protocodec"
+	"go.clocktick.dev/sdk/sdktest"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func init() {
+	protocodec.Register(1, (*wrapperspb.StringValue)(nil))
+}
+
+func TestDeliversMessages(t *testing.T) {
+	pub, priv, err := sdktest.GenerateKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := sdk.NewServerE("", "test-encryption-key", pub, "endpoint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	sdk.AddRoute1(s, "greet", func(_ context.Context, msg *wrapperspb.StringValue) error {
+		got = append(got, msg.GetValue())
+		return nil
+	})
+
+	signer, err := sdktest.NewSigner(priv, "test-encryption-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := signer.Request("greet", "job_1", wrapperspb.String("ada"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", w.Code, w.Body)
+	}
+	if len(got) != 1 || got[0] != "ada" {
+		t.Errorf("handler got %v, want [ada]", got)
+	}
+}
+
+func TestEncodesWithProtobuf(t *testing.T) {
+	b, err := msgpack.Marshal(wrapperspb.String("ada"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The message is written as a msgpack extension rather than as a map of its fields.
+	var v any
+	if err := msgpack.Unmarshal(b, &v); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := v.(map[string]any); ok {
+		t.Errorf("message was encoded field by field: %v", v)
+	}
+}
+
+func TestRegisterPanicsOnNegativeID(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic")
+		}
+	}()
+	protocodec.Register(-1, (*wrapperspb.Int64Value)(nil))
+}
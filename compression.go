@@ -0,0 +1,137 @@
+package sdk
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Compressor is used to compress the arguments of jobs before they are encrypted, which keeps
+// large payloads under the request size limits of the API. The name is written into the
+// payload so that the handler knows how to decompress it, so it must be unique and never
+// change.
+type Compressor interface {
+	Name() string
+	Compress(data []byte) ([]byte, error)
+	Decompress(data []byte) ([]byte, error)
+}
+
+// Gzip is used to compress payloads with gzip. Servers can always decompress it.
+var Gzip Compressor = gzipCompressor{}
+
+type gzipCompressor struct{}
+
+// Name is used to get the name of the compressor.
+func (gzipCompressor) Name() string { return "gzip" }
+
+// Compress is used to compress the data with gzip.
+func (gzipCompressor) Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decompress is used to decompress the data with gzip.
+func (gzipCompressor) Decompress(data []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// WithCompression is used to compress the arguments of the jobs the server schedules with the
+// compressor when they are at least minSize bytes once encoded. Smaller payloads are sent as
+// they are since compressing them rarely helps. The server can decompress jobs compressed with
+// it as well as gzip. Make sure every SDK handling the jobs supports the compressor before
+// turning this on.
+func WithCompression(c Compressor, minSize int) ServerOption {
+	return func(s *Server) {
+		WithClientCompression(c, minSize)(s.Client)
+	}
+}
+
+// WithClientCompression is used to compress the arguments of the jobs the client schedules
+// with the compressor when they are at least minSize bytes once encoded.
+func WithClientCompression(c Compressor, minSize int) ClientOption {
+	return func(cl *Client) {
+		cl.compressor = c
+		cl.compressMinSize = minSize
+	}
+}
+
+// WithDecompressors is used to let the server handle jobs compressed with the compressors
+// without compressing the jobs it schedules, which is useful when rolling out compression.
+func WithDecompressors(cs ...Compressor) ServerOption {
+	return func(s *Server) {
+		s.decompressors = append(s.decompressors, cs...)
+	}
+}
+
+// Compresses the arguments of the envelope if a compressor is set and they are large enough.
+func (c *Client) compressPayload(env payloadEnvelope) (payloadEnvelope, error) {
+	if c.compressor == nil {
+		return env, nil
+	}
+	b, err := msgpack.Marshal(env.Args)
+	if err != nil {
+		return env, err
+	}
+	if len(b) < c.compressMinSize {
+		return env, nil
+	}
+	z, err := c.compressor.Compress(b)
+	if err != nil {
+		return env, fmt.Errorf("failed to compress payload: %w", err)
+	}
+	env.Args = nil
+	env.Compression = c.compressor.Name()
+	env.Compressed = z
+	return env, nil
+}
+
+// Finds the compressor with the name specified.
+func (c *Client) findCompressor(name string) Compressor {
+	if c.compressor != nil && c.compressor.Name() == name {
+		return c.compressor
+	}
+	for _, d := range c.decompressors {
+		if d.Name() == name {
+			return d
+		}
+	}
+	if name == Gzip.Name() {
+		return Gzip
+	}
+	return nil
+}
+
+// Decompresses the arguments of the envelope if they were compressed.
+func (c *Client) decompressPayload(env payloadEnvelope) (payloadEnvelope, error) {
+	if env.Compression == "" {
+		return env, nil
+	}
+	comp := c.findCompressor(env.Compression)
+	if comp == nil {
+		return env, fmt.Errorf("unknown compression %q", env.Compression)
+	}
+	b, err := comp.Decompress(env.Compressed)
+	if err != nil {
+		return env, fmt.Errorf("failed to decompress payload: %w", err)
+	}
+	env.Compressed = nil
+	if err = msgpack.Unmarshal(b, &env.Args); err != nil {
+		return env, err
+	}
+	return env, nil
+}
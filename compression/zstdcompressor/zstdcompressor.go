@@ -0,0 +1,46 @@
+// Package zstdcompressor is used to compress the job arguments of the Clocktick SDK with
+// Zstandard, which is faster and usually smaller than gzip.
+package zstdcompressor
+
+import (
+	"github.com/klauspost/compress/zstd"
+	"go.clocktick.dev/sdk"
+)
+
+// Compressor is used to compress payloads with Zstandard. It is safe to use concurrently.
+type Compressor struct {
+	enc *zstd.Encoder
+	dec *zstd.Decoder
+}
+
+var _ sdk.Compressor = (*Compressor)(nil)
+
+// New is used to create the compressor, passed to sdk.WithCompression or
+// sdk.WithDecompressors.
+func New() *Compressor {
+	// These only fail with invalid options.
+	enc, err := zstd.NewWriter(nil)
+	if err != nil {
+		panic(err)
+	}
+	dec, err := zstd.NewReader(nil)
+	if err != nil {
+		panic(err)
+	}
+	return &Compressor{enc: enc, dec: dec}
+}
+
+// Name is used to get the name of the compressor.
+func (c *Compressor) Name() string {
+	return "zstd"
+}
+
+// Compress is used to compress the data.
+func (c *Compressor) Compress(data []byte) ([]byte, error) {
+	return c.enc.EncodeAll(data, nil), nil
+}
+
+// Decompress is used to decompress the data.
+func (c *Compressor) Decompress(data []byte) ([]byte, error) {
+	return c.dec.DecodeAll(data, nil)
+}
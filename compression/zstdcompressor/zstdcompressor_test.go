@@ -0,0 +1,34 @@
+package zstdcompressor_test
+
+import (
+	"bytes"
+	"testing"
+
+	"go.clocktick.dev/sdk/compression/zstdcompressor"
+)
+
+func TestRoundTrip(t *testing.T) {
+	c := zstdcompressor.New()
+	data := bytes.Repeat([]byte("clocktick "), 100)
+
+	compressed, err := c.Compress(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(compressed) >= len(data) {
+		t.Errorf("compressed to %d bytes from %d", len(compressed), len(data))
+	}
+	got, err := c.Decompress(compressed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Error("decompressed data does not match")
+	}
+}
+
+func TestDecompressRejectsInvalidData(t *testing.T) {
+	if _, err := zstdcompressor.New().Decompress([]byte("not zstd")); err == nil {
+		t.Error("expected an error")
+	}
+}
@@ -0,0 +1,57 @@
+package sdk
+
+import (
+	"net/http"
+	"time"
+)
+
+// Limits how many deliveries run at once.
+type concurrencyLimit struct {
+	sem        chan struct{}
+	retryAfter time.Duration
+}
+
+// MaxConcurrency is used to cap how many deliveries of the route run at once as an option.
+// When the limit is reached, deliveries are rejected with a 429 so the platform re-delivers
+// them later, with a Retry-After header if retryAfter is not 0. Passing the same option to
+// several routes shares the limit between them.
+func MaxConcurrency(n int, retryAfter time.Duration) Option {
+	if n < 1 {
+		panic("n must be at least 1")
+	}
+	return Option{concurrency: &concurrencyLimit{sem: make(chan struct{}, n), retryAfter: retryAfter}}
+}
+
+// Gets the concurrency limit of the route, or nil if it has none.
+func (f funcOpts) concurrencyLimit() *concurrencyLimit {
+	var l *concurrencyLimit
+	for _, opt := range f.a {
+		if opt.concurrency != nil {
+			l = opt.concurrency
+		}
+	}
+	return l
+}
+
+// Tries to take a slot without waiting. Returns false if the limit has been reached.
+func (l *concurrencyLimit) acquire() bool {
+	select {
+	case l.sem <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// Releases a slot taken by acquire.
+func (l *concurrencyLimit) release() {
+	<-l.sem
+}
+
+// Rejects the delivery because the route is at its limit.
+func (l *concurrencyLimit) reject(w http.ResponseWriter, d *delivery) {
+	if l.retryAfter > 0 {
+		w.Header().Set("Retry-After", retryAfterSeconds(l.retryAfter))
+	}
+	d.fail(w, "route is at its concurrency limit", http.StatusTooManyRequests, outcomeThrottled)
+}
@@ -0,0 +1,197 @@
+package sdk
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Config is used to define the settings of a server in a struct, for setups which load them
+// from a config file rather than passing them to NewServer. The tags match the names used in
+// the errors from Validate. Durations are in nanoseconds in JSON. Zero values use the
+// defaults of the matching options.
+type Config struct {
+	// APIKey, EncryptionKey, and PublicKey are passed to NewServer. PublicKey is not needed
+	// if HMACSecret is set.
+	APIKey        string `json:"api_key" yaml:"api_key"`
+	EncryptionKey string `json:"encryption_key" yaml:"encryption_key"`
+	PublicKey     string `json:"public_key" yaml:"public_key"`
+
+	// EndpointID is the endpoint jobs are scheduled against by default.
+	EndpointID string `json:"endpoint_id" yaml:"endpoint_id"`
+
+	// PublicKeys are accepted as well as PublicKey, see WithPublicKeys.
+	PublicKeys []string `json:"public_keys" yaml:"public_keys"`
+
+	// HMACSecret verifies deliveries with HMAC-SHA256 instead, see WithHMACSecret.
+	HMACSecret string `json:"hmac_secret" yaml:"hmac_secret"`
+
+	// BaseURL is the base URL of the API, see WithBaseURL.
+	BaseURL string `json:"base_url" yaml:"base_url"`
+
+	// ProxyURL is the forward proxy API requests are sent through, see WithProxy.
+	ProxyURL string `json:"proxy_url" yaml:"proxy_url"`
+
+	// UserAgent and RequestHeaders are sent with every API request, see WithUserAgent and
+	// WithRequestHeader.
+	UserAgent      string            `json:"user_agent" yaml:"user_agent"`
+	RequestHeaders map[string]string `json:"request_headers" yaml:"request_headers"`
+
+	// MaxBodySize is the maximum size of a delivery body in bytes, see WithMaxBodySize.
+	MaxBodySize int64 `json:"max_body_size" yaml:"max_body_size"`
+
+	// TimestampMaxAge and TimestampMaxSkew are the timestamp window of deliveries, see
+	// WithTimestampWindow.
+	TimestampMaxAge  time.Duration `json:"timestamp_max_age" yaml:"timestamp_max_age"`
+	TimestampMaxSkew time.Duration `json:"timestamp_max_skew" yaml:"timestamp_max_skew"`
+
+	// StrictScheduling turns on WithStrictScheduling.
+	StrictScheduling bool `json:"strict_scheduling" yaml:"strict_scheduling"`
+
+	// MinRecurringInterval is the shortest interval recurring jobs can have, see
+	// WithMinRecurringInterval.
+	MinRecurringInterval time.Duration `json:"min_recurring_interval" yaml:"min_recurring_interval"`
+
+	// BatchWorkers and BatchSize are used by ScheduleJobs, see WithBatchWorkers and
+	// WithBatchSize.
+	BatchWorkers int `json:"batch_workers" yaml:"batch_workers"`
+	BatchSize    int `json:"batch_size" yaml:"batch_size"`
+}
+
+// ConfigFieldError is used to describe a field of a Config which is not valid.
+type ConfigFieldError struct {
+	// Field is the name of the field as it is tagged.
+	Field string
+
+	Err error
+}
+
+// Error is used to convert the config field error to a string.
+func (e *ConfigFieldError) Error() string {
+	return e.Field + ": " + e.Err.Error()
+}
+
+// Unwrap is used to get the reason the field is not valid.
+func (e *ConfigFieldError) Unwrap() error {
+	return e.Err
+}
+
+// Validate is used to check the config, returning every field which is not valid at once as
+// ConfigFieldErrors joined with errors.Join. Returns nil if the config is valid.
+func (c Config) Validate() error {
+	var errs []error
+	fail := func(field string, err error) {
+		errs = append(errs, &ConfigFieldError{Field: field, Err: err})
+	}
+
+	if c.APIKey == "" {
+		fail("api_key", errors.New("is required"))
+	}
+	if c.EncryptionKey == "" {
+		fail("encryption_key", errors.New("is required"))
+	}
+	if c.PublicKey == "" && c.HMACSecret == "" {
+		fail("public_key", errors.New("is required unless hmac_secret is set"))
+	} else if c.PublicKey != "" {
+		if _, err := decodePublicKey(c.PublicKey); err != nil {
+			fail("public_key", err)
+		}
+	}
+	for i, k := range c.PublicKeys {
+		if _, err := decodePublicKey(k); err != nil {
+			fail("public_keys["+strconv.Itoa(i)+"]", err)
+		}
+	}
+	if err := checkConfigURL(c.BaseURL); err != nil {
+		fail("base_url", err)
+	}
+	if err := checkConfigURL(c.ProxyURL); err != nil {
+		fail("proxy_url", err)
+	}
+	if c.MaxBodySize < 0 {
+		fail("max_body_size", errors.New("must not be negative"))
+	}
+	if c.TimestampMaxAge < 0 {
+		fail("timestamp_max_age", errors.New("must not be negative"))
+	}
+	if c.TimestampMaxSkew < 0 {
+		fail("timestamp_max_skew", errors.New("must not be negative"))
+	}
+	if c.MinRecurringInterval < 0 {
+		fail("min_recurring_interval", errors.New("must not be negative"))
+	}
+	if c.BatchWorkers < 0 {
+		fail("batch_workers", errors.New("must not be negative"))
+	}
+	if c.BatchSize < 0 {
+		fail("batch_size", errors.New("must not be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+// Checks the URL is empty or an absolute HTTP or HTTPS URL.
+func checkConfigURL(raw string) error {
+	if raw == "" {
+		return nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("must be an absolute http or https URL")
+	}
+	return nil
+}
+
+// NewServer is used to create a new server from the config after validating it. The options
+// are applied after those from the config, so they take precedence.
+func (c Config) NewServer(opts ...ServerOption) (*Server, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	var configOpts []ServerOption
+	if len(c.PublicKeys) != 0 {
+		configOpts = append(configOpts, WithPublicKeys(c.PublicKeys...))
+	}
+	if c.HMACSecret != "" {
+		configOpts = append(configOpts, WithHMACSecret(c.HMACSecret))
+	}
+	if c.BaseURL != "" {
+		configOpts = append(configOpts, WithBaseURL(c.BaseURL))
+	}
+	if c.ProxyURL != "" {
+		// This was parsed by Validate, so it cannot fail.
+		u, _ := url.Parse(c.ProxyURL)
+		configOpts = append(configOpts, WithProxy(u))
+	}
+	if c.UserAgent != "" {
+		configOpts = append(configOpts, WithUserAgent(c.UserAgent))
+	}
+	for k, v := range c.RequestHeaders {
+		configOpts = append(configOpts, WithRequestHeader(k, v))
+	}
+	if c.MaxBodySize != 0 {
+		configOpts = append(configOpts, WithMaxBodySize(c.MaxBodySize))
+	}
+	if c.TimestampMaxAge != 0 || c.TimestampMaxSkew != 0 {
+		configOpts = append(configOpts, WithTimestampWindow(c.TimestampMaxAge, c.TimestampMaxSkew))
+	}
+	if c.StrictScheduling {
+		configOpts = append(configOpts, WithStrictScheduling())
+	}
+	if c.MinRecurringInterval != 0 {
+		configOpts = append(configOpts, WithMinRecurringInterval(c.MinRecurringInterval))
+	}
+	if c.BatchWorkers != 0 {
+		configOpts = append(configOpts, WithBatchWorkers(c.BatchWorkers))
+	}
+	if c.BatchSize != 0 {
+		configOpts = append(configOpts, WithBatchSize(c.BatchSize))
+	}
+	return NewServerE(
+		c.APIKey, c.EncryptionKey, c.PublicKey, c.EndpointID, append(configOpts, opts...)...,
+	)
+}
@@ -0,0 +1,93 @@
+package sdk
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Defines what happens when a job is scheduled with a custom ID that another job already has.
+// The zero value leaves it to the API, which fails the request.
+type conflictPolicy string
+
+const (
+	conflictUpsert conflictPolicy = "replace"
+	conflictFail   conflictPolicy = "fail"
+)
+
+// Checks the policy is only set on a job with a custom ID.
+func (c conflictPolicy) validate(id string) error {
+	if c != "" && id == "" {
+		return errors.New("Upsert and FailIfExists can only be used with a custom ID")
+	}
+	return nil
+}
+
+// Adds the policy to the skeleton.
+func (c conflictPolicy) apply(data *createJobSkeleton) {
+	data.OnConflict = string(c)
+}
+
+// Wraps a conflict from the API so that it matches ErrJobExists.
+func conflictError(err error) error {
+	var apiErr APIError
+	if errors.As(err, &apiErr) && apiErr.Type == "conflict" {
+		return fmt.Errorf("%w: %w", ErrJobExists, err)
+	}
+	return err
+}
+
+// Upsert is used to replace the job with the same custom ID if there is one, rather than
+// failing. Requires a custom ID.
+func (p FromNowPropertiesBuilder) Upsert() FromNowPropertiesBuilder {
+	p.conflict = conflictUpsert
+	return p
+}
+
+// FailIfExists is used to fail with ErrJobExists if there is already a job with the same
+// custom ID. Requires a custom ID.
+func (p FromNowPropertiesBuilder) FailIfExists() FromNowPropertiesBuilder {
+	p.conflict = conflictFail
+	return p
+}
+
+// Upsert is used to replace the job with the same custom ID if there is one, rather than
+// failing. Requires a custom ID.
+func (p FromTimePropertiesBuilder) Upsert() FromTimePropertiesBuilder {
+	p.conflict = conflictUpsert
+	return p
+}
+
+// FailIfExists is used to fail with ErrJobExists if there is already a job with the same
+// custom ID. Requires a custom ID.
+func (p FromTimePropertiesBuilder) FailIfExists() FromTimePropertiesBuilder {
+	p.conflict = conflictFail
+	return p
+}
+
+// Upsert is used to replace the job with the same custom ID if there is one, rather than
+// failing. Requires a custom ID.
+func (p FromCronPropertiesBuilder) Upsert() FromCronPropertiesBuilder {
+	p.conflict = conflictUpsert
+	return p
+}
+
+// FailIfExists is used to fail with ErrJobExists if there is already a job with the same
+// custom ID. Requires a custom ID.
+func (p FromCronPropertiesBuilder) FailIfExists() FromCronPropertiesBuilder {
+	p.conflict = conflictFail
+	return p
+}
+
+// Upsert is used to replace the job with the same custom ID if there is one, rather than
+// failing. Requires a custom ID.
+func (p FromRRulePropertiesBuilder) Upsert() FromRRulePropertiesBuilder {
+	p.conflict = conflictUpsert
+	return p
+}
+
+// FailIfExists is used to fail with ErrJobExists if there is already a job with the same
+// custom ID. Requires a custom ID.
+func (p FromRRulePropertiesBuilder) FailIfExists() FromRRulePropertiesBuilder {
+	p.conflict = conflictFail
+	return p
+}
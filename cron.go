@@ -0,0 +1,317 @@
+package sdk
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Defines a field in a cron expression.
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int
+}
+
+var (
+	cronMonthNames = map[string]int{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	}
+
+	cronSecond     = cronField{name: "second", min: 0, max: 59}
+	cronMinute     = cronField{name: "minute", min: 0, max: 59}
+	cronHour       = cronField{name: "hour", min: 0, max: 23}
+	cronDayOfMonth = cronField{name: "day of month", min: 1, max: 31}
+	cronMonth      = cronField{name: "month", min: 1, max: 12, names: cronMonthNames}
+	cronYear       = cronField{name: "year", min: 1970, max: 2099}
+
+	// Unix cron numbers the days of the week 0-7 where both 0 and 7 are Sunday.
+	cronUnixDayOfWeek = cronField{
+		name: "day of week", min: 0, max: 7,
+		names: map[string]int{
+			"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+		},
+	}
+
+	// Quartz numbers the days of the week 1-7 where 1 is Sunday.
+	cronQuartzDayOfWeek = cronField{
+		name: "day of week", min: 1, max: 7,
+		names: map[string]int{
+			"SUN": 1, "MON": 2, "TUE": 3, "WED": 4, "THU": 5, "FRI": 6, "SAT": 7,
+		},
+	}
+)
+
+// Defines a parsed cron expression. Each field is the set of values it matches.
+type cronSchedule struct {
+	second, minute, hour, dayOfMonth, month, dayOfWeek []bool
+	year                                               []bool
+
+	// Set when the day field was * or ?, so only the other day field applies.
+	dayOfMonthAny, dayOfWeekAny bool
+}
+
+// Parses a single value in a field.
+func (f cronField) parseValue(s string) (int, error) {
+	if n, ok := f.names[strings.ToUpper(s)]; ok {
+		return n, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s value %q", f.name, s)
+	}
+	if n < f.min || n > f.max {
+		return 0, fmt.Errorf("%s value %d is out of range %d-%d", f.name, n, f.min, f.max)
+	}
+	return n, nil
+}
+
+// Parses a field into the set of values it matches. any is true for * and ?.
+func (f cronField) parse(s string, questionAllowed bool) (set []bool, any bool, err error) {
+	set = make([]bool, f.max-f.min+1)
+	if s == "?" {
+		if !questionAllowed {
+			return nil, false, fmt.Errorf("? is not allowed in the %s field", f.name)
+		}
+		s = "*"
+	}
+	any = s == "*"
+
+	for _, part := range strings.Split(s, ",") {
+		// Handle the step.
+		step := 1
+		if i := strings.IndexByte(part, '/'); i != -1 {
+			step, err = strconv.Atoi(part[i+1:])
+			if err != nil || step < 1 {
+				return nil, false, fmt.Errorf("invalid %s step %q", f.name, part[i+1:])
+			}
+			part = part[:i]
+		}
+
+		// Handle the range.
+		var start, end int
+		switch {
+		case part == "*":
+			start, end = f.min, f.max
+		case strings.Contains(part, "-"):
+			bounds := strings.SplitN(part, "-", 2)
+			if start, err = f.parseValue(bounds[0]); err != nil {
+				return nil, false, err
+			}
+			if end, err = f.parseValue(bounds[1]); err != nil {
+				return nil, false, err
+			}
+			if end < start {
+				return nil, false, fmt.Errorf("%s range %q is backwards", f.name, part)
+			}
+		default:
+			if start, err = f.parseValue(part); err != nil {
+				return nil, false, err
+			}
+			end = start
+			if step != 1 {
+				// a/n means every n starting at a.
+				end = f.max
+			}
+		}
+
+		for v := start; v <= end; v += step {
+			set[v-f.min] = true
+		}
+	}
+	return set, any, nil
+}
+
+// Parses a cron expression with 5 (Unix), 6 (Quartz with seconds), or 7 (Quartz with
+// seconds and year) fields.
+func parseCron(expr string) (*cronSchedule, error) {
+	fields := strings.Fields(expr)
+	quartz := true
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+		quartz = false
+	case 6, 7:
+	default:
+		return nil, fmt.Errorf("cron expression must have 5, 6, or 7 fields, got %d", len(fields))
+	}
+
+	c := &cronSchedule{}
+	var err error
+	if c.second, _, err = cronSecond.parse(fields[0], false); err != nil {
+		return nil, err
+	}
+	if c.minute, _, err = cronMinute.parse(fields[1], false); err != nil {
+		return nil, err
+	}
+	if c.hour, _, err = cronHour.parse(fields[2], false); err != nil {
+		return nil, err
+	}
+	if c.dayOfMonth, c.dayOfMonthAny, err = cronDayOfMonth.parse(fields[3], true); err != nil {
+		return nil, err
+	}
+	if c.month, _, err = cronMonth.parse(fields[4], false); err != nil {
+		return nil, err
+	}
+
+	// Normalise the days of the week to 0-6 where 0 is Sunday.
+	dowField := cronUnixDayOfWeek
+	if quartz {
+		dowField = cronQuartzDayOfWeek
+	}
+	dow, dowAny, err := dowField.parse(fields[5], true)
+	if err != nil {
+		return nil, err
+	}
+	c.dayOfWeek, c.dayOfWeekAny = make([]bool, 7), dowAny
+	for i, ok := range dow {
+		if !ok {
+			continue
+		}
+		v := i + dowField.min
+		if quartz {
+			v--
+		}
+		c.dayOfWeek[v%7] = true
+	}
+	if quartz && fields[3] != "?" && fields[5] != "?" {
+		return nil, errors.New("one of the day of month and day of week fields must be ?")
+	}
+
+	if len(fields) == 7 {
+		if c.year, _, err = cronYear.parse(fields[6], false); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// Checks if the day of the time matches. Like Unix cron, when both day fields are
+// restricted, a day matching either of them matches.
+func (c *cronSchedule) dayMatches(t time.Time) bool {
+	dom := c.dayOfMonth[t.Day()-cronDayOfMonth.min]
+	dow := c.dayOfWeek[t.Weekday()]
+	switch {
+	case c.dayOfMonthAny:
+		return dow
+	case c.dayOfWeekAny:
+		return dom
+	}
+	return dom || dow
+}
+
+// Gets the first time after the time specified which matches the schedule, in the location
+// of the time. This returns false if nothing matches within the next 8 years, which covers
+// schedules only matching the 29th of February.
+func (c *cronSchedule) next(after time.Time) (time.Time, bool) {
+	t := after.Truncate(time.Second).Add(time.Second)
+	loc := t.Location()
+	limit := after.Year() + 8
+	for t.Year() <= limit {
+		switch {
+		case c.year != nil && (t.Year() < cronYear.min || t.Year() > cronYear.max ||
+			!c.year[t.Year()-cronYear.min]):
+			t = time.Date(t.Year()+1, time.January, 1, 0, 0, 0, 0, loc)
+		case !c.month[int(t.Month())-cronMonth.min]:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !c.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case !c.hour[t.Hour()]:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case !c.minute[t.Minute()]:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, loc)
+		case !c.second[t.Second()]:
+			t = t.Add(time.Second)
+		default:
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+type startFromCron struct {
+	Type       string `json:"type"`
+	Expression string `json:"expression"`
+}
+
+// FromCronPropertiesBuilder is used to create a builder for properties.
+type FromCronPropertiesBuilder struct {
+	expr       string
+	id         string
+	headers    map[string]string
+	idemKey    string
+	end        endCondition
+	exclusions exclusions
+	labels     labels
+	conflict   conflictPolicy
+}
+
+// FromCron is used to create a builder for scheduling a job with a cron expression. Standard
+// 5 field expressions are supported, as are Quartz style 6 field expressions with a leading
+// seconds field and 7 field expressions with a trailing year field. Quartz style
+// expressions number the days of the week 1-7 starting on Sunday and require one of the day
+// fields to be ?. The L, W, and # modifiers are not supported.
+func FromCron(expr string) FromCronPropertiesBuilder {
+	return FromCronPropertiesBuilder{expr: expr}
+}
+
+// CustomID is used to set the custom ID of the job.
+func (p FromCronPropertiesBuilder) CustomID(id string) FromCronPropertiesBuilder {
+	p.id = id
+	return p
+}
+
+// Header is used to attach a custom header to the job. Headers are sent back unencrypted
+// on delivery as X-Clocktick-Header-<key> so that they can be read before decryption.
+func (p FromCronPropertiesBuilder) Header(key, value string) FromCronPropertiesBuilder {
+	p.headers = withHeader(p.headers, key, value)
+	return p
+}
+
+// IdempotencyKey is used to set the key the job is created with. The API will not create a
+// second job with the same key, so the request is retried if it fails with a transient error.
+func (p FromCronPropertiesBuilder) IdempotencyKey(key string) FromCronPropertiesBuilder {
+	p.idemKey = key
+	return p
+}
+
+func (p FromCronPropertiesBuilder) buildSkeleton() (id string, data createJobSkeleton) {
+	data = createJobSkeleton{
+		StartFrom: startFromCron{
+			Type:       "cron",
+			Expression: strings.Join(strings.Fields(p.expr), " "),
+		},
+		RunEvery:      nil,
+		EndpointID:    "",
+		EncryptedData: "",
+		JobType:       "",
+		Headers:       p.headers,
+
+		idempotencyKey: p.idemKey,
+	}
+	p.end.apply(&data)
+	p.exclusions.apply(&data)
+	p.labels.apply(&data)
+	p.conflict.apply(&data)
+	return p.id, data
+}
+
+func (p FromCronPropertiesBuilder) validate(now time.Time, strict bool) error {
+	if _, err := parseCron(p.expr); err != nil {
+		return err
+	}
+	if err := p.end.validate(true); err != nil {
+		return err
+	}
+	if err := p.exclusions.validate(); err != nil {
+		return err
+	}
+	if err := p.labels.validate(); err != nil {
+		return err
+	}
+	return p.conflict.validate(p.id)
+}
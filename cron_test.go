@@ -0,0 +1,87 @@
+package sdk_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.clocktick.dev/sdk"
+)
+
+// Gets the time on 2030-month-day at hour:min UTC.
+func utc2030(month time.Month, day, hour, min int) time.Time {
+	return time.Date(2030, month, day, hour, min, 0, 0, time.UTC)
+}
+
+func TestCronExpansion(t *testing.T) {
+	tests := []struct {
+		expr string
+		want []time.Time
+	}{
+		{
+			// Quartz numbers the days from Sunday, so 2 is Monday.
+			expr: "0 30 9 ? * 2 2030",
+			want: []time.Time{
+				utc2030(time.January, 7, 9, 30), utc2030(time.January, 14, 9, 30),
+				utc2030(time.January, 21, 9, 30),
+			},
+		},
+		{
+			expr: "15 0 0 1 1/6 ? 2030",
+			want: []time.Time{
+				time.Date(2030, 1, 1, 0, 0, 15, 0, time.UTC), time.Date(2030, 7, 1, 0, 0, 15, 0, time.UTC),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			assertRuns(t, sdk.PreviewRuns(sdk.FromCron(tt.expr), 3), tt.want...)
+		})
+	}
+}
+
+func TestCronUnixExpression(t *testing.T) {
+	runs := sdk.PreviewRuns(sdk.FromCron("0 9 * * 1"), 3)
+	if len(runs) != 3 {
+		t.Fatalf("got %d runs, want 3", len(runs))
+	}
+	for i, run := range runs {
+		if run.Weekday() != time.Monday || run.Hour() != 9 || run.Minute() != 0 {
+			t.Errorf("run %d = %v, want a Monday at 09:00", i, run)
+		}
+		if i > 0 && run.Sub(runs[i-1]) != 7*24*time.Hour {
+			t.Errorf("run %d is %v after the last, want a week", i, run.Sub(runs[i-1]))
+		}
+	}
+}
+
+func TestCronRejectsInvalidExpressions(t *testing.T) {
+	for _, expr := range []string{"", "* * *", "61 * * * *", "0 0 9 * * 2", "0 0 9 L * ?"} {
+		if runs := sdk.PreviewRuns(sdk.FromCron(expr), 1); runs != nil {
+			t.Errorf("%q: got runs %v, want none", expr, runs)
+		}
+	}
+}
+
+func TestMinRecurringIntervalAppliesToCron(t *testing.T) {
+	api := newRecordingAPI(t)
+	s, _ := newTestServer(t, api.URL, sdk.WithMinRecurringInterval(time.Hour))
+	r, _ := addRecordingRoute(s, "report", nil)
+	ctx := context.Background()
+
+	// The last expression runs twice each morning, a minute apart.
+	for _, expr := range []string{"* * * * * ?", "*/30 * * * *", "0 0,1 9 * * ?"} {
+		_, err := r.Schedule(ctx, sdk.FromCron(expr), "x")
+		var validationErr *sdk.ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Errorf("%q: err = %v, want a ValidationError", expr, err)
+		}
+	}
+	if n := len(api.received()); n != 0 {
+		t.Fatalf("API received %d requests, want 0", n)
+	}
+	if _, err := r.Schedule(ctx, sdk.FromCron("0 9 * * *"), "x"); err != nil {
+		t.Errorf("daily expression: %v", err)
+	}
+}
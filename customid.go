@@ -0,0 +1,43 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+)
+
+// GetJobByCustomID is used to get the job with the custom ID specified, so that the job ID
+// returned when it was scheduled does not need to be stored. Returns ErrJobNotFound if no job
+// has the custom ID.
+func (c *Client) GetJobByCustomID(ctx context.Context, customId string) (Job, error) {
+	if customId == "" {
+		return Job{}, errors.New("custom ID is required")
+	}
+	opts := ListJobsOptions{CustomID: customId}
+	for {
+		list, err := c.ListJobs(ctx, opts)
+		if err != nil {
+			return Job{}, err
+		}
+
+		// Check the custom ID matches rather than trusting the filter.
+		for _, job := range list.Jobs {
+			if job.CustomID == customId {
+				return job, nil
+			}
+		}
+		if list.NextCursor == "" {
+			return Job{}, ErrJobNotFound
+		}
+		opts.Cursor = list.NextCursor
+	}
+}
+
+// DeleteJobByCustomID is used to delete the job with the custom ID specified. Returns
+// ErrJobNotFound if no job has the custom ID.
+func (c *Client) DeleteJobByCustomID(ctx context.Context, customId string) error {
+	job, err := c.GetJobByCustomID(ctx, customId)
+	if err != nil {
+		return err
+	}
+	return c.DeleteJob(ctx, job.ID)
+}
@@ -0,0 +1,148 @@
+package sdk
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// JobFailure defines a single failed run of a job.
+type JobFailure struct {
+	Time time.Time
+	Err  error
+}
+
+// DeadLetter defines a job which has permanently failed and will not be retried.
+type DeadLetter struct {
+	Route string
+	JobID string
+
+	// Args are the arguments of the job decoded into generic values. Arguments which could not
+	// be decoded are left as msgpack.RawMessage.
+	Args []any
+
+	// Failures are the failures of the job in the order they happened, ending with the one
+	// which caused it to be dead-lettered.
+	Failures []JobFailure
+}
+
+// The maximum number of jobs failures are tracked for at once.
+const maxDeadLetterHistory = 10000
+
+// Tracks the failures of jobs and dead-letters them.
+type deadLetters struct {
+	maxFailures int
+	f           func(ctx context.Context, dl DeadLetter)
+
+	mu      sync.Mutex
+	history map[string][]JobFailure
+}
+
+// WithDeadLetter is used to call f with jobs which have permanently failed, so that they can
+// be kept somewhere to be looked at or retried later. A job is dead-lettered when its handler
+// has returned an error maxFailures times in a row, or straight away if it panics or its
+// arguments fail to decode. Dead-lettered deliveries are acknowledged with a 200 so the
+// platform stops re-delivering them. With WithAsyncExecution, failed jobs are dead-lettered
+// straight away since they are never re-delivered. Failures are tracked in memory by job ID,
+// so with several replicas, the count is per replica.
+func WithDeadLetter(maxFailures int, f func(ctx context.Context, dl DeadLetter)) ServerOption {
+	if maxFailures < 1 {
+		panic("maxFailures must be at least 1")
+	}
+	if f == nil {
+		panic("f must not be nil")
+	}
+	return func(s *Server) {
+		s.deadLetters = &deadLetters{
+			maxFailures: maxFailures,
+			f:           f,
+			history:     map[string][]JobFailure{},
+		}
+	}
+}
+
+// Records the failure of the job, returning the failures so far and if the job should now be
+// dead-lettered.
+func (dls *deadLetters) record(jobID string, err error, permanent bool) ([]JobFailure, bool) {
+	dls.mu.Lock()
+	defer dls.mu.Unlock()
+	failures := append(dls.history[jobID], JobFailure{Time: time.Now(), Err: err})
+	if permanent || len(failures) >= dls.maxFailures {
+		delete(dls.history, jobID)
+		return failures, true
+	}
+	if _, ok := dls.history[jobID]; !ok && len(dls.history) >= maxDeadLetterHistory {
+		// Make room by forgetting any job.
+		for k := range dls.history {
+			delete(dls.history, k)
+			break
+		}
+	}
+	dls.history[jobID] = failures
+	return failures, false
+}
+
+// Gets the number of failures of the job so far.
+func (dls *deadLetters) failures(jobID string) int {
+	dls.mu.Lock()
+	defer dls.mu.Unlock()
+	return len(dls.history[jobID])
+}
+
+// Forgets the failures of the job after it succeeds.
+func (dls *deadLetters) reset(jobID string) {
+	dls.mu.Lock()
+	delete(dls.history, jobID)
+	dls.mu.Unlock()
+}
+
+// Records that the job succeeded.
+func (s *Server) jobSucceeded(jobID string) {
+	if s.deadLetters != nil {
+		s.deadLetters.reset(jobID)
+	}
+}
+
+// Records that the job failed and dead-letters it if it has now failed too many times or the
+// failure is permanent. Returns true if the job was dead-lettered, which is false if the
+// dead-letter handler panicked.
+func (s *Server) jobFailed(ctx context.Context, inv *Invocation, err error, permanent bool) bool {
+	if s.deadLetters == nil {
+		return false
+	}
+	failures, dead := s.deadLetters.record(inv.JobID, err, permanent)
+	if !dead {
+		return false
+	}
+
+	// Decode the arguments as best we can.
+	args := make([]any, len(inv.Args))
+	for i, raw := range inv.Args {
+		if msgpack.Unmarshal(raw, &args[i]) != nil {
+			args[i] = raw
+		}
+	}
+
+	dl := DeadLetter{Route: inv.Route, JobID: inv.JobID, Args: args, Failures: failures}
+	s.logger.Error(
+		"job dead-lettered", "route", inv.Route, "job_id", inv.JobID, "failures", len(failures),
+	)
+	if v := panicCondom(func() { s.deadLetters.f(ctx, dl) }); v != nil {
+		// Leave the delivery to fail as normal so the job is not lost.
+		s.logger.Error(
+			"dead-letter handler panicked", "route", inv.Route, "job_id", inv.JobID, "panic", v,
+		)
+		return false
+	}
+	return true
+}
+
+// Acknowledges a dead-lettered delivery so the platform stops re-delivering it.
+func (d *delivery) deadLettered(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusOK)
+	d.status = http.StatusOK
+	d.outcome = outcomeDeadLettered
+}
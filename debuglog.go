@@ -0,0 +1,166 @@
+package sdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// The value redacted headers and fields are replaced with.
+const redacted = "[REDACTED]"
+
+// Headers which are never logged.
+var redactedHeaders = []string{
+	"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie",
+	"X-Signature-Ed25519", HMACSignatureHeader,
+}
+
+// Headers with any of these in their lowercased name are never logged either, since they are
+// likely to hold a secret.
+var redactedHeaderParts = []string{"auth", "token", "secret", "key"}
+
+// WithDebugLogging is used to log the requests the server sends to the API, the responses it
+// gets back, and the deliveries it receives, at the debug level of the logger specified. The
+// API key, signatures, and encrypted data are redacted, but anything else, such as job
+// results, is logged as is. This is meant for diagnosing integration issues, not production.
+func WithDebugLogging(l Logger) ServerOption {
+	return func(s *Server) {
+		s.debugLog = l
+		s.interceptors = append(s.interceptors, debugInterceptor{l: l})
+	}
+}
+
+// WithClientDebugLogging is used to log the requests the client sends to the API and the
+// responses it gets back at the debug level of the logger specified. The API key, signatures,
+// and encrypted data are redacted.
+func WithClientDebugLogging(l Logger) ClientOption {
+	return func(c *Client) {
+		c.interceptors = append(c.interceptors, debugInterceptor{l: l})
+	}
+}
+
+// Logs the requests and responses passing through it.
+type debugInterceptor struct {
+	l Logger
+}
+
+// BeforeSend is used to log the request.
+func (d debugInterceptor) BeforeSend(req *http.Request) error {
+	var body []byte
+	if req.GetBody != nil {
+		if r, err := req.GetBody(); err == nil {
+			body, _ = io.ReadAll(r)
+			_ = r.Close()
+		}
+	}
+	d.l.Debug(
+		"clocktick API request", "method", req.Method, "url", req.URL.String(),
+		"headers", redactHeaders(req.Header), "body", redactBody(body),
+	)
+	return nil
+}
+
+// AfterReceive is used to log the response, putting back the body once it is read.
+func (d debugInterceptor) AfterReceive(req *http.Request, resp *http.Response, err error) {
+	if err != nil {
+		d.l.Debug(
+			"clocktick API request failed", "method", req.Method, "url", req.URL.String(),
+			"error", err,
+		)
+		return
+	}
+	body, readErr := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	var r io.Reader = bytes.NewReader(body)
+	if readErr != nil {
+		// Let the caller see the read failing.
+		r = io.MultiReader(r, errReader{readErr})
+	}
+	resp.Body = io.NopCloser(r)
+	d.l.Debug(
+		"clocktick API response", "method", req.Method, "url", req.URL.String(),
+		"status", resp.StatusCode, "headers", redactHeaders(resp.Header), "body", redactBody(body),
+	)
+}
+
+// Returns the error on every read.
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
+
+// Logs the headers of a delivery if debug logging is on.
+func (s *Server) debugDelivery(r *http.Request) {
+	if s.debugLog != nil {
+		s.debugLog.Debug(
+			"clocktick delivery received", "method", r.Method, "url", r.URL.String(),
+			"headers", redactHeaders(r.Header),
+		)
+	}
+}
+
+// Logs the body of a delivery if debug logging is on.
+func (s *Server) debugDeliveryBody(b []byte) {
+	if s.debugLog != nil {
+		s.debugLog.Debug("clocktick delivery body", "body", redactBody(b))
+	}
+}
+
+// Copies the headers with the secrets in them redacted.
+func redactHeaders(h http.Header) http.Header {
+	h = h.Clone()
+	for _, k := range redactedHeaders {
+		if h.Get(k) != "" {
+			h.Set(k, redacted)
+		}
+	}
+	for k := range h {
+		name := strings.ToLower(k)
+		for _, part := range redactedHeaderParts {
+			if strings.Contains(name, part) {
+				h[k] = []string{redacted}
+				break
+			}
+		}
+	}
+	return h
+}
+
+// Converts the JSON body to a string for logging with any encrypted data redacted. Bodies
+// which are not JSON are only logged by their size, since they cannot be checked.
+func redactBody(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return "<" + strconv.Itoa(len(b)) + " bytes>"
+	}
+	redactValue(v)
+	out, _ := json.Marshal(v)
+	return string(out)
+}
+
+// Redacts the encrypted data fields within the decoded JSON value.
+func redactValue(v any) {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, field := range v {
+			if k == "encrypted_data" {
+				v[k] = redacted
+				continue
+			}
+			redactValue(field)
+		}
+	case []any:
+		for _, item := range v {
+			redactValue(item)
+		}
+	}
+}
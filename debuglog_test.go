@@ -0,0 +1,77 @@
+package sdk_test
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"go.clocktick.dev/sdk"
+)
+
+// Records the headers logged at the debug level.
+type headerLogger struct {
+	mu      sync.Mutex
+	headers []http.Header
+}
+
+func (l *headerLogger) Debug(_ string, keysAndValues ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		if h, ok := keysAndValues[i+1].(http.Header); ok && keysAndValues[i] == "headers" {
+			l.headers = append(l.headers, h)
+		}
+	}
+}
+
+func (*headerLogger) Info(string, ...any)  {}
+func (*headerLogger) Warn(string, ...any)  {}
+func (*headerLogger) Error(string, ...any) {}
+
+func TestDebugLoggingRedactsSecretHeaders(t *testing.T) {
+	api := newRecordingAPI(t)
+	l := &headerLogger{}
+	s, signer := newTestServer(t, api.URL, sdk.WithDebugLogging(l))
+	addRecordingRoute(s, "email", nil)
+
+	req, err := signer.Sign("/", []byte(`{}`), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	secrets := map[string]string{
+		"Proxy-Authorization": "Basic abc",
+		"Cookie":              "session=abc",
+		"Set-Cookie":          "session=abc",
+		"X-Api-Token":         "abc",
+		"X-Client-Secret":     "abc",
+		"X-Api-Key":           "abc",
+		"X-Custom-Auth":       "abc",
+	}
+	for k, v := range secrets {
+		req.Header.Add(k, v)
+	}
+	req.Header.Set("X-Request-Id", "req_1")
+	serve(s, req)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.headers) == 0 {
+		t.Fatal("no headers were logged")
+	}
+	h := l.headers[0]
+	for k := range secrets {
+		if got := h.Values(k); len(got) != 1 || got[0] != "[REDACTED]" {
+			t.Errorf("%s = %v, want it redacted", k, got)
+		}
+	}
+	if got := h.Get("X-Signature-Ed25519"); got != "[REDACTED]" {
+		t.Errorf("signature = %q, want it redacted", got)
+	}
+	if got := h.Get("X-Request-Id"); got != "req_1" {
+		t.Errorf("X-Request-Id = %q, want it logged", got)
+	}
+	if got := req.Header.Get("Cookie"); got != "session=abc" {
+		t.Errorf("request Cookie = %q, want it left alone", got)
+	}
+}
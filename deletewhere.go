@@ -0,0 +1,91 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// DeleteJobsFilter is used to pick the jobs deleted by DeleteJobsWhere. Jobs must match every
+// field which is set, and at least one field must be set.
+type DeleteJobsFilter struct {
+	// Route limits the deletion to jobs for this route.
+	Route string
+
+	// Tag limits the deletion to jobs with this tag.
+	Tag string
+
+	// CustomIDPrefix limits the deletion to jobs with a custom ID starting with this prefix.
+	CustomIDPrefix string
+
+	// EndpointID limits the deletion to jobs for this endpoint.
+	EndpointID string
+}
+
+var errEmptyDeleteFilter = errors.New("filter must have at least one field set")
+
+// Checks if the job matches every field of the filter which is set.
+func (f DeleteJobsFilter) matches(job Job) bool {
+	if f.Route != "" && job.Route != f.Route {
+		return false
+	}
+	if f.EndpointID != "" && job.EndpointID != f.EndpointID {
+		return false
+	}
+	if f.CustomIDPrefix != "" && !strings.HasPrefix(job.CustomID, f.CustomIDPrefix) {
+		return false
+	}
+	if f.Tag == "" {
+		return true
+	}
+	for _, tag := range job.Tags {
+		if tag == f.Tag {
+			return true
+		}
+	}
+	return false
+}
+
+// DeleteJobsWhere is used to delete every job matching the filter, such as all of the jobs
+// tagged for a customer when they close their account. The matching jobs are listed before
+// any are deleted, so jobs created during the call may be missed. Jobs which are already
+// gone are skipped. Returns the number of jobs deleted, which is how far it got if an error
+// is returned.
+func (c *Client) DeleteJobsWhere(ctx context.Context, filter DeleteJobsFilter) (int, error) {
+	if filter == (DeleteJobsFilter{}) {
+		return 0, errEmptyDeleteFilter
+	}
+
+	// Collect the IDs first since deleting jobs moves the cursor.
+	opts := ListJobsOptions{Route: filter.Route, Tag: filter.Tag, EndpointID: filter.EndpointID}
+	var ids []string
+	for {
+		list, err := c.ListJobs(ctx, opts)
+		if err != nil {
+			return 0, err
+		}
+		for _, job := range list.Jobs {
+			if filter.matches(job) {
+				ids = append(ids, job.ID)
+			}
+		}
+		if list.NextCursor == "" {
+			break
+		}
+		opts.Cursor = list.NextCursor
+	}
+
+	deleted := 0
+	for _, id := range ids {
+		err := c.DeleteJob(ctx, id)
+		var apiErr APIError
+		if errors.As(err, &apiErr) && apiErr.Type == "not_found" {
+			continue
+		}
+		if err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
@@ -0,0 +1,212 @@
+package sdk_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"go.clocktick.dev/sdk"
+	"go.clocktick.dev/sdk/sdktest"
+)
+
+// Builds a delivery of "a" to the email route, signed by the signer at the time specified.
+func signedDelivery(t *testing.T, signer *sdktest.Signer, ts time.Time) *http.Request {
+	t.Helper()
+	body, err := signer.Body("email", "job_1", "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := signer.Sign("/", body, ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
+
+func TestServeHTTPRunsSignedDelivery(t *testing.T) {
+	api := newRecordingAPI(t)
+	s, signer := newTestServer(t, api.URL)
+	_, calls := addRecordingRoute(s, "email", nil)
+
+	if w := serve(s, signedDelivery(t, signer, time.Now())); w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", w.Code, w.Body)
+	}
+	if got := calls(); len(got) != 1 || got[0] != "a" {
+		t.Errorf("handler got %v, want [a]", got)
+	}
+}
+
+func TestServeHTTPRejectsBadSignatures(t *testing.T) {
+	api := newRecordingAPI(t)
+	s, signer := newTestServer(t, api.URL)
+	_, calls := addRecordingRoute(s, "email", nil)
+	other, err := sdktest.NewSigner(otherPrivateKey(t), testEncryptionKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		req  func() *http.Request
+		want int
+	}{
+		{
+			name: "wrong key",
+			req:  func() *http.Request { return signedDelivery(t, other, time.Now()) },
+			want: http.StatusUnauthorized,
+		},
+		{
+			name: "missing signature",
+			req: func() *http.Request {
+				req := signedDelivery(t, signer, time.Now())
+				req.Header.Del("X-Signature-Ed25519")
+				return req
+			},
+			want: http.StatusBadRequest,
+		},
+		{
+			name: "missing timestamp",
+			req: func() *http.Request {
+				req := signedDelivery(t, signer, time.Now())
+				req.Header.Del("X-Signature-Timestamp")
+				return req
+			},
+			want: http.StatusBadRequest,
+		},
+		{
+			name: "signature not hex",
+			req: func() *http.Request {
+				req := signedDelivery(t, signer, time.Now())
+				req.Header.Set("X-Signature-Ed25519", "not hex")
+				return req
+			},
+			want: http.StatusBadRequest,
+		},
+		{
+			name: "timestamp changed",
+			req: func() *http.Request {
+				req := signedDelivery(t, signer, time.Now())
+				req.Header.Set("X-Signature-Timestamp", "1")
+				return req
+			},
+			want: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := serve(s, tt.req()); w.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.want, w.Body)
+			}
+		})
+	}
+	if got := calls(); len(got) != 0 {
+		t.Errorf("handler was called with %v", got)
+	}
+}
+
+func TestServeHTTPRejectsTimestampsOutsideWindow(t *testing.T) {
+	api := newRecordingAPI(t)
+	s, signer := newTestServer(t, api.URL, sdk.WithTimestampWindow(time.Minute, 30*time.Second))
+	_, calls := addRecordingRoute(s, "email", nil)
+
+	for name, ts := range map[string]time.Time{
+		"outdated":  time.Now().Add(-2 * time.Minute),
+		"in future": time.Now().Add(time.Minute),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if w := serve(s, signedDelivery(t, signer, ts)); w.Code != http.StatusUnauthorized {
+				t.Errorf("status = %d, want 401", w.Code)
+			}
+		})
+	}
+	if got := calls(); len(got) != 0 {
+		t.Errorf("handler was called with %v", got)
+	}
+
+	// Within the window the delivery is accepted.
+	if w := serve(s, signedDelivery(t, signer, time.Now().Add(-30*time.Second))); w.Code != http.StatusOK {
+		t.Errorf("status within the window = %d, want 200", w.Code)
+	}
+}
+
+func TestServeHTTPRejectsReplays(t *testing.T) {
+	api := newRecordingAPI(t)
+	s, signer := newTestServer(t, api.URL, sdk.WithReplayStore(sdk.NewMemoryReplayStore(10)))
+	_, calls := addRecordingRoute(s, "email", nil)
+
+	body, err := signer.Body("email", "job_1", "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := time.Now()
+	sign := func() *http.Request {
+		req, err := signer.Sign("/", body, ts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return req
+	}
+	if w := serve(s, sign()); w.Code != http.StatusOK {
+		t.Fatalf("first delivery status = %d", w.Code)
+	}
+
+	// Sending the same request again, even with the hex in upper case, is rejected.
+	for _, upper := range []bool{false, true} {
+		req := sign()
+		if upper {
+			req.Header.Set("X-Signature-Ed25519", strings.ToUpper(req.Header.Get("X-Signature-Ed25519")))
+		}
+		if w := serve(s, req); w.Code != http.StatusConflict {
+			t.Errorf("replay status = %d, want 409", w.Code)
+		}
+	}
+	if got := calls(); len(got) != 1 {
+		t.Errorf("handler was called %d times, want 1", len(got))
+	}
+}
+
+func TestServeHTTPRejectsLargeBodies(t *testing.T) {
+	api := newRecordingAPI(t)
+	s, signer := newTestServer(t, api.URL, sdk.WithMaxBodySize(64))
+	_, calls := addRecordingRoute(s, "email", nil)
+
+	req, err := signer.Sign("/", []byte(`{"type":"email","pad":"`+strings.Repeat("x", 100)+`"}`), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w := serve(s, req); w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want 413", w.Code)
+	}
+	if got := calls(); len(got) != 0 {
+		t.Errorf("handler was called with %v", got)
+	}
+}
+
+func TestServeHTTPVerifiesHMAC(t *testing.T) {
+	api := newRecordingAPI(t)
+	s := newTestServerWithKey(t, api.URL, "", sdk.WithHMACSecret("secret"))
+	_, calls := addRecordingRoute(s, "email", nil)
+
+	for secret, want := range map[string]int{
+		"secret": http.StatusOK,
+		"other":  http.StatusUnauthorized,
+	} {
+		signer, err := sdktest.NewSigner(nil, testEncryptionKey, sdktest.WithHMACSecret(secret))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if w := serve(s, signedDelivery(t, signer, time.Now())); w.Code != want {
+			t.Errorf("signed with %q: status = %d, want %d", secret, w.Code, want)
+		}
+	}
+
+	// Ed25519 signatures are not accepted once HMAC is on.
+	_, ed := newTestServer(t, api.URL)
+	if w := serve(s, signedDelivery(t, ed, time.Now())); w.Code != http.StatusBadRequest {
+		t.Errorf("Ed25519 delivery status = %d, want 400", w.Code)
+	}
+	if got := calls(); len(got) != 1 {
+		t.Errorf("handler was called %d times, want 1", len(got))
+	}
+}
@@ -0,0 +1,121 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// WithDialContext is used to set the function the server's HTTP client dials connections
+// with. This only applies when the client uses a *http.Transport, which is true of the
+// default client. Calling SetClient afterwards replaces the client and this with it. Under
+// js/wasm, setting this stops the transport from using the fetch API.
+func WithDialContext(dial func(ctx context.Context, network, addr string) (net.Conn, error)) ServerOption {
+	return func(s *Server) {
+		s.client = withTransport(s.client, func(t *http.Transport) {
+			t.DialContext = dial
+		})
+	}
+}
+
+type cachedAddrs struct {
+	addrs   []string
+	expires time.Time
+}
+
+// CachingDialer is used to dial connections whilst caching the DNS lookups for the hosts
+// dialed. If a lookup fails once an entry has expired, the stale addresses are used so that
+// resolver problems do not stop requests. Pass its DialContext method to WithDialContext.
+type CachingDialer struct {
+	// Dialer is the dialer used to make the connections. If nil, a dialer with a 10 second
+	// timeout is used.
+	Dialer *net.Dialer
+
+	// Resolver is the resolver used to lookup hosts. If nil, net.DefaultResolver is used.
+	Resolver *net.Resolver
+
+	// TTL is how long a lookup is cached for.
+	TTL time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cachedAddrs
+}
+
+// NewCachingDialer is used to create a caching dialer with the TTL specified.
+func NewCachingDialer(ttl time.Duration) *CachingDialer {
+	return &CachingDialer{TTL: ttl}
+}
+
+// Looks up the host, using the cache where possible.
+func (d *CachingDialer) lookup(ctx context.Context, host string) ([]string, error) {
+	d.mu.Lock()
+	entry, ok := d.cache[host]
+	d.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.addrs, nil
+	}
+
+	resolver := d.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	addrs, err := resolver.LookupHost(ctx, host)
+	if err != nil || len(addrs) == 0 {
+		if ok {
+			// Serve the stale addresses rather than failing.
+			return entry.addrs, nil
+		}
+		if err == nil {
+			err = errors.New("no addresses found for " + host)
+		}
+		return nil, err
+	}
+
+	d.mu.Lock()
+	if d.cache == nil {
+		d.cache = make(map[string]cachedAddrs)
+	}
+	d.cache[host] = cachedAddrs{addrs: addrs, expires: time.Now().Add(d.TTL)}
+	d.mu.Unlock()
+	return addrs, nil
+}
+
+// DialContext is used to dial the address specified using the cached lookups.
+func (d *CachingDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	dialer := d.Dialer
+	if dialer == nil {
+		dialer = &net.Dialer{Timeout: 10 * time.Second, KeepAlive: 30 * time.Second}
+	}
+
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	if net.ParseIP(host) != nil {
+		// No lookup is required.
+		return dialer.DialContext(ctx, network, addr)
+	}
+	addrs, err := d.lookup(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	// Try each address in turn.
+	var firstErr error
+	for _, ip := range addrs {
+		conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(ip, port))
+		if err == nil {
+			return conn, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, firstErr
+}
@@ -0,0 +1,78 @@
+//go:build !tinygo
+
+package sdk
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+var (
+	customEncoderType   = reflect.TypeOf((*msgpack.CustomEncoder)(nil)).Elem()
+	msgpackMarshalType  = reflect.TypeOf((*msgpack.Marshaler)(nil)).Elem()
+	binaryMarshalerType = reflect.TypeOf((*encoding.BinaryMarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// Checks if the type encodes itself rather than being encoded from its fields.
+func encodesItself(t reflect.Type) bool {
+	for _, it := range []reflect.Type{
+		customEncoderType, msgpackMarshalType, binaryMarshalerType, textMarshalerType,
+	} {
+		if t.Implements(it) || reflect.PointerTo(t).Implements(it) {
+			return true
+		}
+	}
+	return false
+}
+
+// Checks a value of the type can be encoded and decoded with msgpack without being lost,
+// returning why not if it cannot. Types being checked are held in seen so that recursive
+// types terminate.
+func checkEncodable(t reflect.Type, seen map[reflect.Type]bool) error {
+	if seen[t] || encodesItself(t) {
+		return nil
+	}
+	seen[t] = true
+
+	switch t.Kind() {
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer, reflect.Complex64, reflect.Complex128:
+		return fmt.Errorf("%s cannot be encoded", t)
+	case reflect.Pointer, reflect.Slice, reflect.Array:
+		return checkEncodable(t.Elem(), seen)
+	case reflect.Map:
+		if err := checkEncodable(t.Key(), seen); err != nil {
+			return err
+		}
+		return checkEncodable(t.Elem(), seen)
+	case reflect.Struct:
+		exported := false
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.Tag.Get("msgpack") == "-" || (!f.IsExported() && !f.Anonymous) {
+				continue
+			}
+			exported = true
+			if err := checkEncodable(f.Type, seen); err != nil {
+				return fmt.Errorf("field %s of %s: %w", f.Name, t, err)
+			}
+		}
+		if !exported && t.NumField() != 0 {
+			// Everything in the struct would be dropped when it is encoded.
+			return fmt.Errorf("%s has no exported fields", t)
+		}
+	}
+	return nil
+}
+
+// Panics if the parameter of the route at the index, which is of type T, cannot be sent. This
+// is used by the typed routes, which cannot return an error.
+func checkParam[T any](route string, index int) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if err := checkEncodable(t, map[reflect.Type]bool{}); err != nil {
+		panic(fmt.Errorf("parameter %d of route %q cannot be sent: %w", index, route, err))
+	}
+}
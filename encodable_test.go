@@ -0,0 +1,51 @@
+//go:build !tinygo
+
+package sdk_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.clocktick.dev/sdk"
+)
+
+type noExportedFields struct {
+	name string
+}
+
+func TestAddRouteRejectsUnencodableParameters(t *testing.T) {
+	s, _ := newTestServer(t, "http://localhost")
+	err := s.AddRouteE("notify", func(context.Context, string, chan int) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "parameter 1") {
+		t.Errorf("err = %v, want parameter 1 to be rejected", err)
+	}
+}
+
+func TestTypedRoutesRejectUnencodableParameters(t *testing.T) {
+	s, _ := newTestServer(t, "http://localhost")
+	tests := map[string]func(){
+		"AddRoute1": func() {
+			sdk.AddRoute1(s, "one", func(context.Context, func()) error { return nil })
+		},
+		"AddRoute2": func() {
+			sdk.AddRoute2(s, "two", func(context.Context, string, noExportedFields) error { return nil })
+		},
+		"AddRoute3": func() {
+			sdk.AddRoute3(s, "three", func(context.Context, int, string, []chan int) error { return nil })
+		},
+	}
+	for name, add := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected a panic")
+				}
+			}()
+			add()
+		})
+	}
+
+	// The routes are not added when they are rejected.
+	sdk.AddRoute1(s, "one", func(context.Context, string) error { return nil })
+}
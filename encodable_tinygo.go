@@ -0,0 +1,6 @@
+//go:build tinygo
+
+package sdk
+
+// Parameters are not checked under TinyGo, where reflection is limited.
+func checkParam[T any](route string, index int) {}
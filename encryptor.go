@@ -0,0 +1,105 @@
+package sdk
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+	"strings"
+)
+
+// Encryptor is used to encrypt the arguments of jobs when they are scheduled and decrypt them
+// when they are delivered. Implement this to keep the key in a KMS or HSM rather than in
+// process memory. The encrypted data must be a string, and whatever encrypts the jobs must
+// be able to decrypt everything the other SDKs handling them encrypt.
+type Encryptor interface {
+	Encrypt(ctx context.Context, plaintext []byte) (string, error)
+	Decrypt(ctx context.Context, ciphertext string) ([]byte, error)
+}
+
+// WithEncryptor is used to set the encryptor the server uses instead of the encryption key.
+// When this is set, the encryption key passed to NewServerE can be empty.
+func WithEncryptor(e Encryptor) ServerOption {
+	return func(s *Server) {
+		s.Client.encryptor = e
+	}
+}
+
+// WithClientEncryptor is used to set the encryptor the client encrypts job arguments with
+// instead of an encryption key.
+func WithClientEncryptor(e Encryptor) ClientOption {
+	return func(c *Client) {
+		c.encryptor = e
+	}
+}
+
+// The encryptor used for encryption keys. The data is base64(nonce):base64(ciphertext), with
+// a prefix for ciphers other than AES-GCM.
+type aeadEncryptor struct {
+	keys   *ciphers
+	cipher Cipher
+	random io.Reader
+}
+
+// NewKeyEncryptor is used to create the encryptor the SDK uses for an encryption key, which
+// encrypts with the cipher specified. This is useful for wrapping the built in encryption or
+// for building deliveries in tests, such as with the sdktest package.
+func NewKeyEncryptor(encryptionKey string, c Cipher) (Encryptor, error) {
+	keys, err := newCiphers(encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	return &aeadEncryptor{keys: keys, cipher: c, random: rand.Reader}, nil
+}
+
+// Configures the built in encryptor once the options have been applied.
+func (c *Client) configureEncryptor() {
+	if e, ok := c.encryptor.(*aeadEncryptor); ok {
+		e.cipher = c.cipher
+		e.random = c.randomReader()
+	}
+}
+
+// Encrypt is used to encrypt the data with the cipher of the encryptor.
+func (e *aeadEncryptor) Encrypt(_ context.Context, data []byte) (string, error) {
+	aead, prefix := e.keys.get(e.cipher)
+
+	// Generate a random nonce for the local scope.
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(e.random, nonce); err != nil {
+		return "", err
+	}
+	var encryptedData []byte
+	encryptedData = aead.Seal(encryptedData, nonce, data, nil)
+	s := base64.StdEncoding.EncodeToString(nonce) + ":" + base64.StdEncoding.EncodeToString(encryptedData)
+	if prefix != "" {
+		s = prefix + ":" + s
+	}
+	return s, nil
+}
+
+// Decrypt is used to decrypt the data, using the cipher its prefix says it was encrypted with.
+func (e *aeadEncryptor) Decrypt(_ context.Context, data string) ([]byte, error) {
+	aead, _ := e.keys.get(CipherAESGCM)
+	if rest, ok := strings.CutPrefix(data, xchachaPrefix+":"); ok {
+		aead, _ = e.keys.get(CipherXChaCha20Poly1305)
+		data = rest
+	}
+	parts := strings.SplitN(data, ":", 2)
+	if len(parts) != 2 {
+		return nil, errors.New("invalid data")
+	}
+	nonce, err := base64.StdEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, errors.New("invalid nonce size")
+	}
+	encryptedData, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, err
+	}
+	return aead.Open(nil, nonce, encryptedData, nil)
+}
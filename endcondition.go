@@ -0,0 +1,94 @@
+package sdk
+
+import (
+	"errors"
+	"time"
+)
+
+// Defines when a recurring job stops running. The zero value never stops.
+type endCondition struct {
+	until   time.Time
+	maxRuns uint
+}
+
+// Checks if an end condition is set.
+func (e endCondition) isSet() bool {
+	return !e.until.IsZero() || e.maxRuns != 0
+}
+
+// Checks the end condition is only set on a recurring job.
+func (e endCondition) validate(recurring bool) error {
+	if e.isSet() && !recurring {
+		return errors.New("Until and MaxRuns can only be used with recurring jobs")
+	}
+	return nil
+}
+
+// Adds the end condition to the skeleton.
+func (e endCondition) apply(data *createJobSkeleton) {
+	if !e.until.IsZero() {
+		data.Until = formatDatetime(e.until)
+	}
+	data.MaxRuns = e.maxRuns
+}
+
+// Wraps the function getting the next run of a job so that it stops at the end condition.
+// The first run counts as a run.
+func (e endCondition) limit(
+	next func(prev time.Time) (time.Time, bool),
+) func(prev time.Time) (time.Time, bool) {
+	if next == nil || !e.isSet() {
+		return next
+	}
+	runs := uint(1)
+	return func(prev time.Time) (time.Time, bool) {
+		if e.maxRuns != 0 && runs >= e.maxRuns {
+			return time.Time{}, false
+		}
+		n, ok := next(prev)
+		if !ok || (!e.until.IsZero() && n.After(e.until)) {
+			return time.Time{}, false
+		}
+		runs++
+		return n, true
+	}
+}
+
+// Until is used to stop the recurring job after the time specified. The job does not run
+// after this time.
+func (p FromNowPropertiesBuilder) Until(t time.Time) FromNowPropertiesBuilder {
+	p.end.until = t
+	return p
+}
+
+// MaxRuns is used to stop the recurring job after it has run the number of times specified.
+func (p FromNowPropertiesBuilder) MaxRuns(n uint) FromNowPropertiesBuilder {
+	p.end.maxRuns = n
+	return p
+}
+
+// Until is used to stop the recurring job after the time specified. The job does not run
+// after this time.
+func (p FromTimePropertiesBuilder) Until(t time.Time) FromTimePropertiesBuilder {
+	p.end.until = t
+	return p
+}
+
+// MaxRuns is used to stop the recurring job after it has run the number of times specified.
+func (p FromTimePropertiesBuilder) MaxRuns(n uint) FromTimePropertiesBuilder {
+	p.end.maxRuns = n
+	return p
+}
+
+// Until is used to stop the job after the time specified. The job does not run after this
+// time.
+func (p FromCronPropertiesBuilder) Until(t time.Time) FromCronPropertiesBuilder {
+	p.end.until = t
+	return p
+}
+
+// MaxRuns is used to stop the job after it has run the number of times specified.
+func (p FromCronPropertiesBuilder) MaxRuns(n uint) FromCronPropertiesBuilder {
+	p.end.maxRuns = n
+	return p
+}
@@ -0,0 +1,63 @@
+package sdk
+
+import (
+	"os"
+	"strings"
+)
+
+// The environment variables read by NewServerFromEnv.
+const (
+	EnvAPIKey        = "CLOCKTICK_API_KEY"
+	EnvEncryptionKey = "CLOCKTICK_ENCRYPTION_KEY"
+	EnvPublicKey     = "CLOCKTICK_PUBLIC_KEY"
+	EnvEndpointID    = "CLOCKTICK_ENDPOINT_ID"
+	EnvBaseURL       = "CLOCKTICK_BASE_URL"
+	EnvHMACSecret    = "CLOCKTICK_HMAC_SECRET"
+)
+
+// MissingEnvError is returned by NewServerFromEnv when required environment variables are not
+// set.
+type MissingEnvError struct {
+	// Names are the names of the variables which are not set.
+	Names []string
+}
+
+// Error is used to convert the missing environment error to a string.
+func (e *MissingEnvError) Error() string {
+	return "missing environment variables: " + strings.Join(e.Names, ", ")
+}
+
+// NewServerFromEnv is used to create a new server from the CLOCKTICK_API_KEY,
+// CLOCKTICK_ENCRYPTION_KEY, CLOCKTICK_PUBLIC_KEY, and CLOCKTICK_ENDPOINT_ID environment
+// variables, which are all required. CLOCKTICK_BASE_URL optionally sets the base URL, and
+// CLOCKTICK_HMAC_SECRET optionally verifies deliveries with WithHMACSecret, in which case
+// CLOCKTICK_PUBLIC_KEY is not required. Returns a MissingEnvError naming every required
+// variable which is not set. The options are applied after those from the environment, so
+// they take precedence.
+func NewServerFromEnv(opts ...ServerOption) (*Server, error) {
+	var missing []string
+	get := func(name string, required bool) string {
+		v := os.Getenv(name)
+		if v == "" && required {
+			missing = append(missing, name)
+		}
+		return v
+	}
+	hmacSecret := get(EnvHMACSecret, false)
+	apiKey := get(EnvAPIKey, true)
+	encryptionKey := get(EnvEncryptionKey, true)
+	publicKey := get(EnvPublicKey, hmacSecret == "")
+	endpointID := get(EnvEndpointID, true)
+	if missing != nil {
+		return nil, &MissingEnvError{Names: missing}
+	}
+
+	var envOpts []ServerOption
+	if baseURL := get(EnvBaseURL, false); baseURL != "" {
+		envOpts = append(envOpts, WithBaseURL(baseURL))
+	}
+	if hmacSecret != "" {
+		envOpts = append(envOpts, WithHMACSecret(hmacSecret))
+	}
+	return NewServerE(apiKey, encryptionKey, publicKey, endpointID, append(envOpts, opts...)...)
+}
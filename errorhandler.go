@@ -0,0 +1,29 @@
+package sdk
+
+import "context"
+
+// SetErrorHandler is used to set the function called when a delivery fails. It is called for
+// rejected signatures and timestamps, payloads that fail to decrypt or decode, and handlers
+// that return an error. The route is empty if the delivery failed before it was known. Panics
+// go to the panic handler instead.
+func (s *Server) SetErrorHandler(f func(ctx context.Context, route string, err error)) {
+	s.errorHandler = f
+}
+
+// WithErrorHandler is used to set the function called when a delivery fails. See
+// SetErrorHandler for when it is called.
+func WithErrorHandler(f func(ctx context.Context, route string, err error)) ServerOption {
+	return func(s *Server) {
+		s.errorHandler = f
+	}
+}
+
+// Calls the error handler if one is set, making sure a panic in it does not escape.
+func (s *Server) handleError(ctx context.Context, route string, err error) {
+	if s.errorHandler == nil {
+		return
+	}
+	if v := panicCondom(func() { s.errorHandler(ctx, route, err) }); v != nil {
+		s.logger.Error("error handler panicked", "route", route, "panic", v)
+	}
+}
@@ -0,0 +1,66 @@
+package sdk
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	// ErrRouteNotFound is returned when a job is scheduled against a route the server does
+	// not have.
+	ErrRouteNotFound = errors.New("route not found")
+
+	// ErrArgumentMismatch is returned when a job is scheduled with a different number of
+	// arguments to the parameters of its route.
+	ErrArgumentMismatch = errors.New("argument count mismatch")
+
+	// ErrJobExists is returned when a job is scheduled with a custom ID that another job
+	// already has, unless Upsert is used.
+	ErrJobExists = errors.New("a job with this custom ID already exists")
+
+	// ErrJobNotFound is returned when no job has the custom ID specified, or in local mode
+	// when no job has the ID specified.
+	ErrJobNotFound = errors.New("job not found")
+)
+
+// ValidationError is returned when a schedule is rejected before it is sent to the API. It
+// unwraps to the reason the schedule was rejected.
+type ValidationError struct {
+	Err error
+}
+
+// Error is used to convert the validation error to a string.
+func (e *ValidationError) Error() string {
+	return "invalid schedule: " + e.Err.Error()
+}
+
+// Unwrap is used to get the reason the schedule was rejected.
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// AuthError is returned when the API responds with 401 Unauthorized or 403 Forbidden, which
+// usually means the API key is wrong or does not have access to the resource. It unwraps to
+// the RequestError for the response.
+type AuthError struct {
+	RequestError
+}
+
+// Error is used to convert the auth error to a string.
+func (e AuthError) Error() string {
+	if e.Status == http.StatusForbidden {
+		return "API key does not have access to the resource"
+	}
+	return "API key was rejected"
+}
+
+// Unwrap is used to get the RequestError for the response.
+func (e AuthError) Unwrap() error {
+	return e.RequestError
+}
+
+// Builds the error for a job with the wrong number of arguments.
+func argumentMismatch(expected, got int) error {
+	return fmt.Errorf("%w: expected %d, got %d", ErrArgumentMismatch, expected, got)
+}
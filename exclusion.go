@@ -0,0 +1,209 @@
+package sdk
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ExclusionWindow defines a period of time in which a job must not run. A run which falls
+// within the window is deferred to the end of it. Times are in the timezone of the job, or
+// UTC if it does not have one.
+type ExclusionWindow struct {
+	// Weekdays are the days the window starts on. If empty, the window applies every day.
+	Weekdays []time.Weekday
+
+	// Start and End are the time of day the window covers as offsets from midnight. If End
+	// is before Start, the window wraps past midnight into the next day. If both are 0, the
+	// whole day is covered.
+	Start, End time.Duration
+}
+
+// ExcludeWeekends is used to make an exclusion window covering all of Saturday and Sunday.
+func ExcludeWeekends() ExclusionWindow {
+	return ExclusionWindow{Weekdays: []time.Weekday{time.Saturday, time.Sunday}}
+}
+
+// ExcludeTimeOfDay is used to make an exclusion window covering from start until end every
+// day. For example, ExcludeTimeOfDay(0, 6*time.Hour) skips 00:00 to 06:00.
+func ExcludeTimeOfDay(start, end time.Duration) ExclusionWindow {
+	return ExclusionWindow{Start: start, End: end}
+}
+
+// Checks the window is within a day and is not empty.
+func (w ExclusionWindow) validate() error {
+	const day = 24 * time.Hour
+	if w.Start < 0 || w.Start >= day || w.End < 0 || w.End > day {
+		return errors.New("exclusion window times must be within a day")
+	}
+	if w.Start%time.Minute != 0 || w.End%time.Minute != 0 {
+		return errors.New("exclusion window times must be whole minutes")
+	}
+	if w.Start == w.End && w.Start != 0 {
+		return errors.New("exclusion window is empty")
+	}
+	for _, d := range w.Weekdays {
+		if d < time.Sunday || d > time.Saturday {
+			return fmt.Errorf("invalid weekday %d", d)
+		}
+	}
+	return nil
+}
+
+// Checks if the window applies to the weekday.
+func (w ExclusionWindow) onDay(d time.Weekday) bool {
+	if len(w.Weekdays) == 0 {
+		return true
+	}
+	for _, wd := range w.Weekdays {
+		if wd == d {
+			return true
+		}
+	}
+	return false
+}
+
+// Gets the end of the window if the time falls within it.
+func (w ExclusionWindow) endOf(t time.Time) (time.Time, bool) {
+	y, m, d := t.Date()
+	midnight := time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	tod := t.Sub(midnight)
+	end := w.End
+	if w.Start == 0 && end == 0 {
+		end = 24 * time.Hour
+	}
+
+	if end > w.Start {
+		if w.onDay(t.Weekday()) && tod >= w.Start && tod < end {
+			return midnight.Add(end), true
+		}
+		return time.Time{}, false
+	}
+
+	// The window wraps past midnight, so either it started today or yesterday.
+	if w.onDay(t.Weekday()) && tod >= w.Start {
+		return time.Date(y, m, d+1, 0, 0, 0, 0, t.Location()).Add(end), true
+	}
+	if w.onDay(t.AddDate(0, 0, -1).Weekday()) && tod < end {
+		return midnight.Add(end), true
+	}
+	return time.Time{}, false
+}
+
+// Defines the exclusion window in the format the API expects.
+type exclusionWindowJSON struct {
+	Weekdays []string `json:"weekdays,omitempty"`
+	Start    string   `json:"start"`
+	End      string   `json:"end"`
+}
+
+// Formats a time of day as HH:MM.
+func formatTimeOfDay(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", d/time.Hour, d%time.Hour/time.Minute)
+}
+
+// Defines the exclusion windows of a job.
+type exclusions []ExclusionWindow
+
+// Adds the windows without changing the ones builders may share.
+func (e exclusions) add(ws []ExclusionWindow) exclusions {
+	return append(e[:len(e):len(e)], ws...)
+}
+
+// Checks all the windows are valid.
+func (e exclusions) validate() error {
+	for _, w := range e {
+		if err := w.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Adds the windows to the skeleton.
+func (e exclusions) apply(data *createJobSkeleton) {
+	if len(e) == 0 {
+		return
+	}
+	data.Exclusions = make([]exclusionWindowJSON, len(e))
+	for i, w := range e {
+		var days []string
+		for _, d := range w.Weekdays {
+			days = append(days, strings.ToLower(d.String()))
+		}
+		data.Exclusions[i] = exclusionWindowJSON{
+			Weekdays: days,
+			Start:    formatTimeOfDay(w.Start),
+			End:      formatTimeOfDay(w.End),
+		}
+	}
+}
+
+// Defers the time until it is outside every window.
+func (e exclusions) deferTime(t time.Time) time.Time {
+	// Bound the loop so that windows covering all of the time cannot hang.
+	for i := 0; i < 100; i++ {
+		moved := false
+		for _, w := range e {
+			if end, ok := w.endOf(t); ok {
+				t = end
+				moved = true
+			}
+		}
+		if !moved {
+			break
+		}
+	}
+	return t
+}
+
+// Wraps the runs of a job so that they are deferred out of the windows in the location
+// specified. The schedule carries on from the runs as they would have been without the
+// windows, and runs deferred to the same time are only run once.
+func (e exclusions) wrap(
+	loc *time.Location, first time.Time, next func(prev time.Time) (time.Time, bool),
+) (time.Time, func(prev time.Time) (time.Time, bool)) {
+	if len(e) == 0 {
+		return first, next
+	}
+	deferredFirst := e.deferTime(first.In(loc))
+	if next == nil {
+		return deferredFirst, nil
+	}
+	raw := first
+	return deferredFirst, func(prev time.Time) (time.Time, bool) {
+		for i := 0; i < 1<<20; i++ {
+			n, ok := next(raw)
+			if !ok {
+				return time.Time{}, false
+			}
+			raw = n
+			if d := e.deferTime(n.In(loc)); d.After(prev) {
+				return d, true
+			}
+		}
+		return time.Time{}, false
+	}
+}
+
+// Exclude is used to defer runs of the job which fall within any of the windows to the end
+// of the window.
+func (p FromNowPropertiesBuilder) Exclude(windows ...ExclusionWindow) FromNowPropertiesBuilder {
+	p.exclusions = p.exclusions.add(windows)
+	return p
+}
+
+// Exclude is used to defer runs of the job which fall within any of the windows to the end
+// of the window.
+func (p FromTimePropertiesBuilder) Exclude(windows ...ExclusionWindow) FromTimePropertiesBuilder {
+	p.exclusions = p.exclusions.add(windows)
+	return p
+}
+
+// Exclude is used to defer runs of the job which fall within any of the windows to the end
+// of the window.
+func (p FromCronPropertiesBuilder) Exclude(windows ...ExclusionWindow) FromCronPropertiesBuilder {
+	p.exclusions = p.exclusions.add(windows)
+	return p
+}
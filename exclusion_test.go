@@ -0,0 +1,68 @@
+package sdk_test
+
+import (
+	"testing"
+	"time"
+
+	"go.clocktick.dev/sdk"
+)
+
+// Checks the runs match the times specified.
+func assertRuns(t *testing.T, got []time.Time, want ...time.Time) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d runs %v, want %d runs %v", len(got), got, len(want), want)
+	}
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Errorf("run %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+// Gets the time on 2030-01-day at hour:00 UTC. 4 January 2030 is a Friday.
+func jan2030(day, hour int) time.Time {
+	return time.Date(2030, 1, day, hour, 0, 0, 0, time.UTC)
+}
+
+func TestExcludeDefersRuns(t *testing.T) {
+	props := sdk.FromTime(jan2030(4, 12)).EveryDays(1).Exclude(sdk.ExcludeWeekends())
+	assertRuns(t, sdk.PreviewRuns(props, 3), jan2030(4, 12), jan2030(7, 0), jan2030(7, 12))
+}
+
+func TestExcludeTimeOfDayWrapsPastMidnight(t *testing.T) {
+	props := sdk.FromTime(jan2030(3, 21)).EveryHours(1).
+		Exclude(sdk.ExcludeTimeOfDay(22*time.Hour, 2*time.Hour))
+	assertRuns(t, sdk.PreviewRuns(props, 3), jan2030(3, 21), jan2030(4, 2), jan2030(4, 3))
+}
+
+func TestMaxRunsCountsDeferredRuns(t *testing.T) {
+	// The weekend runs are all deferred to Monday midnight and only run once, so the fourth
+	// run is on Monday rather than the schedule ending after three.
+	props := sdk.FromTime(jan2030(4, 22)).EveryHours(1).MaxRuns(4).Exclude(sdk.ExcludeWeekends())
+	assertRuns(
+		t, sdk.PreviewRuns(props, 10),
+		jan2030(4, 22), jan2030(4, 23), jan2030(7, 0), jan2030(7, 1),
+	)
+}
+
+func TestUntilAppliesToDeferredRuns(t *testing.T) {
+	// The Saturday run would be deferred to Monday, which is after Until.
+	props := sdk.FromTime(jan2030(3, 12)).EveryDays(1).Until(jan2030(6, 23)).
+		Exclude(sdk.ExcludeWeekends())
+	assertRuns(t, sdk.PreviewRuns(props, 10), jan2030(3, 12), jan2030(4, 12))
+}
+
+func TestExclusionWindowValidation(t *testing.T) {
+	for name, w := range map[string]sdk.ExclusionWindow{
+		"outside day": {Start: 25 * time.Hour, End: time.Hour},
+		"seconds":     {Start: time.Second, End: time.Hour},
+		"empty":       {Start: time.Hour, End: time.Hour},
+		"weekday":     {Weekdays: []time.Weekday{9}},
+	} {
+		props := sdk.FromTime(jan2030(3, 12)).EveryDays(1).Exclude(w)
+		if runs := sdk.PreviewRuns(props, 1); runs != nil {
+			t.Errorf("%s: window was accepted, got runs %v", name, runs)
+		}
+	}
+}
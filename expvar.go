@@ -0,0 +1,35 @@
+package sdk
+
+import "expvar"
+
+// The names of the counters published by WithExpvar.
+const (
+	counterDeliveries           = "deliveries"
+	counterVerificationFailures = "verification_failures"
+	counterScheduleCalls        = "schedule_calls"
+	counterRetries              = "retries"
+	counterPanics               = "panics"
+)
+
+// WithExpvar is used to publish the server's internal counters as an expvar map with the name
+// specified, so that they show up on /debug/vars. Like expvar.NewMap, this panics if the name
+// is already in use.
+func WithExpvar(name string) ServerOption {
+	return func(s *Server) {
+		m := expvar.NewMap(name)
+		for _, k := range []string{
+			counterDeliveries, counterVerificationFailures, counterScheduleCalls,
+			counterRetries, counterPanics,
+		} {
+			m.Add(k, 0)
+		}
+		s.vars = m
+	}
+}
+
+// Increments the counter specified if expvar publishing is enabled.
+func (s *Server) incr(counter string) {
+	if s.vars != nil {
+		s.vars.Add(counter, 1)
+	}
+}
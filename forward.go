@@ -0,0 +1,54 @@
+package sdk
+
+import (
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+)
+
+// SetFallbackForwarder is used to set the handler verified deliveries for routes which are not
+// registered on this server are passed to. The request is passed on with its original headers
+// and body, so the handler can verify it again. This is useful when moving job handlers
+// between services.
+func (s *Server) SetFallbackForwarder(h http.Handler) {
+	s.fallback = h
+}
+
+// SetFallbackURL is used to proxy verified deliveries for routes which are not registered on
+// this server to the URL specified. See SetFallbackForwarder.
+func (s *Server) SetFallbackURL(target string) error {
+	u, err := url.Parse(target)
+	if err != nil {
+		return err
+	}
+	s.fallback = &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			out := *u
+			pr.Out.URL = &out
+			pr.Out.Host = u.Host
+			pr.SetXForwarded()
+		},
+	}
+	return nil
+}
+
+// Records the status code written to the response. status starts as 200 since that is what
+// is sent if the handler writes nothing.
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
@@ -2,6 +2,12 @@ module go.clocktick.dev/sdk
 
 go 1.20
 
-require github.com/vmihailenco/msgpack/v5 v5.4.1
+require (
+	github.com/vmihailenco/msgpack/v5 v5.4.1
+	golang.org/x/crypto v0.21.0
+)
 
-require github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+require (
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+	golang.org/x/sys v0.18.0 // indirect
+)
@@ -3,21 +3,15 @@ package sdk
 import (
 	"bytes"
 	"context"
-	"crypto/aes"
-	"crypto/cipher"
 	"crypto/ed25519"
-	"crypto/rand"
-	"crypto/sha256"
-	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"expvar"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
-	"os"
-	"reflect"
 	"strconv"
 	"strings"
 	"time"
@@ -28,6 +22,11 @@ import (
 // Option defines the structure of an option in the SDK.
 type Option struct {
 	customEndpointId *string
+	canary           *canary
+	argumentMismatch *ArgumentMismatchPolicy
+	concurrency      *concurrencyLimit
+	version          *uint
+	migrator         Migrator
 }
 
 // CustomEndpointID is used to set the custom endpoint ID as an option.
@@ -35,61 +34,148 @@ func CustomEndpointID(customEndpointId string) Option {
 	return Option{customEndpointId: &customEndpointId}
 }
 
+// ServerOption defines an option that can be passed to NewServer.
+type ServerOption func(*Server)
+
 type funcOpts struct {
-	f any
-	a []Option
+	f       any
+	numArgs int
+	call    func(ctx context.Context, raws []msgpack.RawMessage) (any, error)
+	a       []Option
+
+	// Checks the arguments decode without calling the function. nil if this is not known.
+	check func(raws []msgpack.RawMessage) error
 }
 
-// Server is used to define the structure of a server in the SDK.
-type Server struct {
-	client            *http.Client
-	apiKey            string
-	encryptionKey     cipher.AEAD
-	publicKey         ed25519.PublicKey
-	defaultEndpointId string
-	funcMap           map[string]funcOpts
-	panicHandler      func(any)
+// Checks the encoded arguments decode into the parameters of the handler, so that a type
+// mismatch fails when the job is scheduled rather than when it is delivered. Routes which do
+// not know their parameter types, such as dispatchers, are not checked.
+func (f funcOpts) checkArgs(raws []msgpack.RawMessage) error {
+	if f.check == nil {
+		return nil
+	}
+	return f.check(raws)
 }
 
-func defaultPanicHandler(err any) {
-	fmt.Fprintln(os.Stderr, "panic whilst running job:", err)
+// Server is used to define the structure of a server in the SDK. The embedded Client is used
+// for the job management methods.
+type Server struct {
+	*Client
+
+	publicKeys       []ed25519.PublicKey
+	hmacSecret       []byte
+	funcMap          map[string]funcOpts
+	routeVersions    map[string]map[uint]funcOpts
+	panicHandler     func(any)
+	panicInfoHandler func(ctx context.Context, info PanicInfo)
+	errorHandler     func(ctx context.Context, route string, err error)
+	reporter         Reporter
+	baggageKeys      []any
+	strict           bool
+	clock            func() time.Time
+
+	minRecurringInterval time.Duration
+	batchWorkers         int
+	batchSize            int
+	vars                 *expvar.Map
+	logger               Logger
+	accessLog            Logger
+	debugLog             Logger
+	chaos                *chaos
+	fallback             http.Handler
+	middleware           []Middleware
+	metrics              Metrics
+	replay               ReplayStore
+	timestampMaxAge      time.Duration
+	timestampMaxSkew     time.Duration
+	maxBodySize          int64
+	local                *localScheduler
+	buffer               *scheduleBuffer
+	async                *asyncPool
+	deadLetters          *deadLetters
+
+	// The first error from an option, returned by NewServerE.
+	optErr error
 }
 
-// NewServer is used to create a new server.
+// NewServer is used to create a new server. It panics if the keys are not valid, use
+// NewServerE to handle this as an error instead.
 func NewServer(
 	apiKey string, encryptionKey string, publicKey string,
-	defaultEndpointId string,
+	defaultEndpointId string, opts ...ServerOption,
 ) *Server {
-	// Hash the encryption key with sha256.
-	encryptionKeyBytes := []byte(encryptionKey)
-	encryptionKeyHash := sha256.Sum256(encryptionKeyBytes)
-
-	// Turn it into a encryptor.
-	block, err := aes.NewCipher(encryptionKeyHash[:])
+	s, err := NewServerE(apiKey, encryptionKey, publicKey, defaultEndpointId, opts...)
 	if err != nil {
 		panic(err)
 	}
-	gcm, err := cipher.NewGCM(block)
-	if err != nil {
-		panic(err)
+	return s
+}
+
+// NewServerE is used to create a new server, returning an error if the encryption key is
+// empty without an Encryptor being set, the public key is not a hex encoded ed25519 public
+// key or is empty without WithHMACSecret, or an option is invalid.
+func NewServerE(
+	apiKey string, encryptionKey string, publicKey string,
+	defaultEndpointId string, opts ...ServerOption,
+) (*Server, error) {
+
+	// Decode the public key from hex. It is checked for after the options are applied, since
+	// it is not needed with an HMAC secret.
+	var publicKeys []ed25519.PublicKey
+	if publicKey != "" {
+		pub, err := decodePublicKey(publicKey)
+		if err != nil {
+			return nil, err
+		}
+		publicKeys = append(publicKeys, pub)
 	}
 
-	// Decode the public key from hex.
-	publicKeyBytes, err := hex.DecodeString(publicKey)
-	if err != nil {
-		panic(err)
+	// Create the server and apply the options.
+	c := NewClient(apiKey)
+	if encryptionKey != "" {
+		keys, err := newCiphers(encryptionKey)
+		if err != nil {
+			return nil, err
+		}
+		c.encryptor = &aeadEncryptor{keys: keys}
+	}
+	c.defaultEndpointId = defaultEndpointId
+	s := &Server{
+		Client:        c,
+		publicKeys:    publicKeys,
+		funcMap:       make(map[string]funcOpts),
+		routeVersions: make(map[string]map[uint]funcOpts),
+		panicHandler:  defaultPanicHandler,
+		logger:        nopLogger{},
+		metrics:       nopMetrics{},
+	}
+	c.onRetry = func() { s.incr(counterRetries) }
+	for _, opt := range opts {
+		opt(s)
 	}
+	if s.optErr != nil {
+		return nil, s.optErr
+	}
+	if len(s.publicKeys) == 0 && s.hmacSecret == nil {
+		return nil, errors.New("public key is required")
+	}
+	if c.encryptor == nil {
+		return nil, errors.New("encryption key is required")
+	}
+	c.configureEncryptor()
+	return s, nil
+}
 
-	// Create the server.
-	return &Server{
-		client:            http.DefaultClient,
-		apiKey:            apiKey,
-		encryptionKey:     gcm,
-		publicKey:         ed25519.PublicKey(publicKeyBytes),
-		defaultEndpointId: defaultEndpointId,
-		funcMap:           make(map[string]funcOpts),
-		panicHandler:      defaultPanicHandler,
+// Decodes a hex encoded ed25519 public key.
+func decodePublicKey(publicKey string) (ed25519.PublicKey, error) {
+	b, err := hex.DecodeString(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("public key is not valid hex: %w", err)
+	}
+	if len(b) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("public key must be %d bytes, got %d", ed25519.PublicKeySize, len(b))
 	}
+	return ed25519.PublicKey(b), nil
 }
 
 // SetClient is used to set the HTTP client of the server.
@@ -102,62 +188,54 @@ func (s *Server) SetPanicHandler(f func(any)) {
 	s.panicHandler = f
 }
 
-// AddRoute is used to add a route to the server. f MUST be a function that takes in a
-// context.Context and any other number of arguments.
-func (s *Server) AddRoute(route string, f any, opts ...Option) {
-	// Validate the function.
-	fv := reflect.ValueOf(f)
-	if fv.Kind() != reflect.Func {
-		panic("f must be a function")
+// WithHTTPClient is used to set the HTTP client the server makes API requests with.
+func WithHTTPClient(client *http.Client) ServerOption {
+	return func(s *Server) {
+		s.client = client
 	}
-	if fv.Type().NumIn() < 1 || fv.Type().In(0) != reflect.TypeOf((*context.Context)(nil)).Elem() {
-		panic("f must take in a context.Context as the first argument")
-	}
-
-	// Add the function to the map.
-	s.funcMap[route] = funcOpts{f: f, a: opts}
 }
 
-// JobCreationResponse defines the structure of a job creation response in the SDK.
-type JobCreationResponse struct {
-	JobID string `json:"job_id"`
+// WithBaseURL is used to set the base URL of the Clocktick API. This is useful for pointing
+// the server at a staging environment or a local mock. Defaults to DefaultBaseURL.
+func WithBaseURL(baseURL string) ServerOption {
+	return func(s *Server) {
+		s.baseURL = strings.TrimRight(baseURL, "/")
+	}
 }
 
-var staticNonce []byte
-
-// Encrypts the data specified.
-func (s *Server) encrypt(data []byte) string {
-	nonce := staticNonce
-	if nonce == nil {
-		// Generate a random nonce for the local scope.
-		nonce = make([]byte, s.encryptionKey.NonceSize())
-		if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
-			panic(err)
-		}
+// WithPanicHandler is used to set the function called when a handler panics.
+func WithPanicHandler(f func(any)) ServerOption {
+	return func(s *Server) {
+		s.panicHandler = f
 	}
-	var encryptedData []byte
-	encryptedData = s.encryptionKey.Seal(encryptedData, nonce, data, nil)
-	return base64.StdEncoding.EncodeToString(nonce) + ":" + base64.StdEncoding.EncodeToString(encryptedData)
 }
 
-// Decrypts the data specified.
-func (s *Server) decrypt(data string) ([]byte, error) {
-	parts := strings.SplitN(data, ":", 2)
-	if len(parts) != 2 {
-		return nil, errors.New("invalid data")
+// Dispatcher is used to run a job from its raw msgpack arguments. Routes added with a
+// Dispatcher do not need reflection to be called. If an argument cannot be decoded, the
+// dispatcher should return an error wrapped with ArgumentError.
+type Dispatcher func(ctx context.Context, args []msgpack.RawMessage) error
+
+// AddDispatcher is used to add a route to the server which is run by the dispatcher. argCount
+// is the number of arguments the route takes, not including the context. Panics if the route
+// has already been added.
+func (s *Server) AddDispatcher(route string, argCount int, d Dispatcher, opts ...Option) {
+	if d == nil {
+		panic("d must not be nil")
 	}
-	nonce, err := base64.StdEncoding.DecodeString(parts[0])
-	if err != nil {
-		return nil, err
+	if argCount < 0 {
+		panic("argCount must not be negative")
 	}
-	if len(nonce) != s.encryptionKey.NonceSize() {
-		return nil, errors.New("invalid nonce size")
+	call := func(ctx context.Context, raws []msgpack.RawMessage) (any, error) {
+		return nil, d(ctx, raws)
 	}
-	encryptedData, err := base64.StdEncoding.DecodeString(parts[1])
-	if err != nil {
-		return nil, err
+	if err := s.addRoute(route, funcOpts{numArgs: argCount, call: call, a: opts}); err != nil {
+		panic(err)
 	}
-	return s.encryptionKey.Open(nil, nonce, encryptedData, nil)
+}
+
+// JobCreationResponse defines the structure of a job creation response in the SDK.
+type JobCreationResponse struct {
+	JobID string `json:"job_id"`
 }
 
 // Delta is used to define the structure of a delta in the SDK.
@@ -168,26 +246,69 @@ type Delta struct {
 	Hours   uint `json:"hours"`
 	Minutes uint `json:"minutes"`
 	Seconds uint `json:"seconds"`
+
+	// Milliseconds is only sent when set so that older API versions accept the delta.
+	Milliseconds uint `json:"milliseconds,omitempty"`
+}
+
+// Converts the duration to a delta made of hours and smaller units.
+func durationDelta(d time.Duration) Delta {
+	return Delta{
+		Hours:        uint(d / time.Hour),
+		Minutes:      uint(d % time.Hour / time.Minute),
+		Seconds:      uint(d % time.Minute / time.Second),
+		Milliseconds: uint(d % time.Second / time.Millisecond),
+	}
 }
 
 type createJobSkeleton struct {
-	StartFrom     any    `json:"start_from"`
-	RunEvery      *Delta `json:"run_every"`
-	EndpointID    string `json:"endpoint_id"`
-	EncryptedData string `json:"encrypted_data"`
-	JobType       string `json:"job_type"`
+	StartFrom     any                   `json:"start_from"`
+	RunEvery      *Delta                `json:"run_every"`
+	EndpointID    string                `json:"endpoint_id"`
+	EncryptedData string                `json:"encrypted_data"`
+	JobType       string                `json:"job_type"`
+	Headers       map[string]string     `json:"headers,omitempty"`
+	Timezone      string                `json:"timezone,omitempty"`
+	Until         string                `json:"until,omitempty"`
+	MaxRuns       uint                  `json:"max_runs,omitempty"`
+	Exclusions    []exclusionWindowJSON `json:"exclusions,omitempty"`
+	Tags          []string              `json:"tags,omitempty"`
+	Metadata      map[string]string     `json:"metadata,omitempty"`
+	OnConflict    string                `json:"on_conflict,omitempty"`
+
+	// Sent as the Idempotency-Key header rather than in the body.
+	idempotencyKey string
+}
+
+// Copies the headers map with the key set so builders sharing a map are not mutated.
+func withHeader(headers map[string]string, key, value string) map[string]string {
+	m := make(map[string]string, len(headers)+1)
+	for k, v := range headers {
+		m[k] = v
+	}
+	m[key] = value
+	return m
 }
 
 // ScheduleJobPropertiesBuilder is used to define a properties builder.
 type ScheduleJobPropertiesBuilder interface {
 	buildSkeleton() (id string, data createJobSkeleton)
+	validate(now time.Time, strict bool) error
 }
 
 // FromNowPropertiesBuilder is used to create a builder for properties.
 type FromNowPropertiesBuilder struct {
-	d         Delta
-	id        string
-	recurring bool
+	d          Delta
+	id         string
+	recurring  bool
+	headers    map[string]string
+	anchor     time.Time
+	idemKey    string
+	end        endCondition
+	maxJitter  time.Duration
+	exclusions exclusions
+	labels     labels
+	conflict   conflictPolicy
 }
 
 // Years is used to add years to the delta.
@@ -226,6 +347,12 @@ func (p FromNowPropertiesBuilder) Seconds(seconds uint) FromNowPropertiesBuilder
 	return p
 }
 
+// Milliseconds is used to add milliseconds to the delta.
+func (p FromNowPropertiesBuilder) Milliseconds(milliseconds uint) FromNowPropertiesBuilder {
+	p.d.Milliseconds += milliseconds
+	return p
+}
+
 // CustomID is used to set the custom ID of the job.
 func (p FromNowPropertiesBuilder) CustomID(id string) FromNowPropertiesBuilder {
 	p.id = id
@@ -238,20 +365,52 @@ func (p FromNowPropertiesBuilder) Recurring() FromNowPropertiesBuilder {
 	return p
 }
 
+// Header is used to attach a custom header to the job. Headers are sent back unencrypted
+// on delivery as X-Clocktick-Header-<key> so that they can be read before decryption.
+func (p FromNowPropertiesBuilder) Header(key, value string) FromNowPropertiesBuilder {
+	p.headers = withHeader(p.headers, key, value)
+	return p
+}
+
+// IdempotencyKey is used to set the key the job is created with. The API will not create a
+// second job with the same key, so the request is retried if it fails with a transient error.
+func (p FromNowPropertiesBuilder) IdempotencyKey(key string) FromNowPropertiesBuilder {
+	p.idemKey = key
+	return p
+}
+
 func (p FromNowPropertiesBuilder) buildSkeleton() (id string, data createJobSkeleton) {
-	j, _ := json.Marshal(p.d)
-	typeInjected := append([]byte(`{"type":"delta",`), j[1:]...)
+	var startFrom any
+	jitter := jitterOffset(p.maxJitter)
+	if p.anchor.IsZero() {
+		j, _ := json.Marshal(p.d.plus(durationDelta(jitter)))
+		startFrom = json.RawMessage(append([]byte(`{"type":"delta",`), j[1:]...))
+	} else {
+		// Resolve the delta against the anchor so the API gets an absolute time.
+		startFrom = startFromDatetime{
+			Type:     "datetime",
+			DateTime: formatDatetime(p.d.addTo(p.anchor).Add(jitter)),
+		}
+	}
 	var runEvery *Delta
 	if p.recurring {
 		runEvery = &p.d
 	}
-	return p.id, createJobSkeleton{
-		StartFrom:     json.RawMessage(typeInjected),
+	data = createJobSkeleton{
+		StartFrom:     startFrom,
 		RunEvery:      runEvery,
 		EndpointID:    "",
 		EncryptedData: "",
 		JobType:       "",
+		Headers:       p.headers,
+
+		idempotencyKey: p.idemKey,
 	}
+	p.end.apply(&data)
+	p.exclusions.apply(&data)
+	p.labels.apply(&data)
+	p.conflict.apply(&data)
+	return p.id, data
 }
 
 // FromNow is used to create a builder for scheduling a job from now.
@@ -259,11 +418,45 @@ func FromNow() FromNowPropertiesBuilder {
 	return FromNowPropertiesBuilder{}
 }
 
+// FromDuration is used to create a builder for scheduling a job the duration specified from
+// now. The duration is split into hours, minutes, seconds, and milliseconds so that it stays
+// exact across daylight saving changes, and anything under a millisecond is dropped. Use
+// Recurring to run the job every duration.
+func FromDuration(d time.Duration) FromNowPropertiesBuilder {
+	if d < 0 {
+		panic("d must not be negative")
+	}
+	return FromNowPropertiesBuilder{d: durationDelta(d)}
+}
+
+// FromNowAt is used to create a builder for scheduling a job relative to the time specified
+// rather than the time the job is created. This is useful for tests and backfills.
+func FromNowAt(t time.Time) FromNowPropertiesBuilder {
+	return FromNowPropertiesBuilder{anchor: t}
+}
+
+// Anchors the builder to the time specified if it is not anchored already.
+func (p FromNowPropertiesBuilder) anchorTo(t time.Time) ScheduleJobPropertiesBuilder {
+	if p.anchor.IsZero() {
+		p.anchor = t
+	}
+	return p
+}
+
 // FromTimePropertiesBuilder is used to create a builder for properties.
 type FromTimePropertiesBuilder struct {
-	t  time.Time
-	id string
-	d  *Delta
+	t          time.Time
+	id         string
+	d          *Delta
+	headers    map[string]string
+	timezone   string
+	idemKey    string
+	end        endCondition
+	maxJitter  time.Duration
+	exclusions exclusions
+	labels     labels
+	conflict   conflictPolicy
+	local      *localAnchor
 }
 
 // EveryYears is used to add years to the delta.
@@ -320,12 +513,66 @@ func (p FromTimePropertiesBuilder) EverySeconds(seconds uint) FromTimeProperties
 	return p
 }
 
+// EveryMilliseconds is used to add milliseconds to the delta.
+func (p FromTimePropertiesBuilder) EveryMilliseconds(milliseconds uint) FromTimePropertiesBuilder {
+	if p.d == nil {
+		p.d = &Delta{}
+	}
+	p.d.Milliseconds += milliseconds
+	return p
+}
+
 // CustomID is used to set the custom ID of the job.
 func (p FromTimePropertiesBuilder) CustomID(id string) FromTimePropertiesBuilder {
 	p.id = id
 	return p
 }
 
+// Timezone is used to set the IANA timezone (for example, "Europe/London") the job recurs in.
+// When set, the API adds the delta to the local wall clock time rather than UTC, so a job
+// which runs every day at 09:00 keeps running at 09:00 across DST transitions.
+func (p FromTimePropertiesBuilder) Timezone(name string) FromTimePropertiesBuilder {
+	p.timezone = name
+	return p
+}
+
+// InLocation is used to set the timezone the job recurs in from a location. See Timezone.
+// For time.Local, the IANA name of the local timezone is used.
+func (p FromTimePropertiesBuilder) InLocation(loc *time.Location) FromTimePropertiesBuilder {
+	if loc == time.Local {
+		return p.Timezone(localZoneName())
+	}
+	return p.Timezone(loc.String())
+}
+
+// Header is used to attach a custom header to the job. Headers are sent back unencrypted
+// on delivery as X-Clocktick-Header-<key> so that they can be read before decryption.
+func (p FromTimePropertiesBuilder) Header(key, value string) FromTimePropertiesBuilder {
+	p.headers = withHeader(p.headers, key, value)
+	return p
+}
+
+// IdempotencyKey is used to set the key the job is created with. The API will not create a
+// second job with the same key, so the request is retried if it fails with a transient error.
+func (p FromTimePropertiesBuilder) IdempotencyKey(key string) FromTimePropertiesBuilder {
+	p.idemKey = key
+	return p
+}
+
+// FromTime is used to create a builder for scheduling a job from the time specified.
+func FromTime(t time.Time) FromTimePropertiesBuilder {
+	return FromTimePropertiesBuilder{t: t}
+}
+
+// Formats the time as a UTC ISO 8601 string, only including milliseconds if there are any.
+func formatDatetime(t time.Time) string {
+	t = t.UTC()
+	if t.Nanosecond()/int(time.Millisecond) != 0 {
+		return t.Format("2006-01-02T15:04:05.000Z")
+	}
+	return t.Format("2006-01-02T15:04:05Z")
+}
+
 type startFromDatetime struct {
 	Type     string `json:"type"`
 	DateTime string `json:"datetime"`
@@ -333,10 +580,10 @@ type startFromDatetime struct {
 
 func (p FromTimePropertiesBuilder) buildSkeleton() (id string, data createJobSkeleton) {
 	// Format the time as a UTC ISO 8601 string.
-	formattedTime := p.t.UTC().Format("2006-01-02T15:04:05Z")
+	formattedTime := formatDatetime(p.t.Add(jitterOffset(p.maxJitter)))
 
 	// Return the ID and skeleton.
-	return p.id, createJobSkeleton{
+	data = createJobSkeleton{
 		StartFrom: startFromDatetime{
 			Type:     "datetime",
 			DateTime: formattedTime,
@@ -345,7 +592,16 @@ func (p FromTimePropertiesBuilder) buildSkeleton() (id string, data createJobSke
 		EndpointID:    "",
 		EncryptedData: "",
 		JobType:       "",
+		Headers:       p.headers,
+		Timezone:      p.timezone,
+
+		idempotencyKey: p.idemKey,
 	}
+	p.end.apply(&data)
+	p.exclusions.apply(&data)
+	p.labels.apply(&data)
+	p.conflict.apply(&data)
+	return p.id, data
 }
 
 // APIError is used to define the structure of an API error in the SDK.
@@ -372,11 +628,11 @@ func (e RequestError) Error() string {
 
 func sendRequest(
 	ctx context.Context, client *http.Client, apiKey string, reqUrl string, method string,
-	body any, respBody any,
+	header http.Header, body any, respBody any, interceptors []Interceptor,
 ) error {
-	// Use DefaultClient if client is nil.
+	// Use the default client if client is nil.
 	if client == nil {
-		client = http.DefaultClient
+		client = defaultClient
 	}
 
 	// Build the request.
@@ -392,13 +648,16 @@ func sendRequest(
 	if err != nil {
 		return err
 	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
 	req.Header.Set("Authorization", "Bearer "+apiKey)
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
 
 	// Send the request.
-	resp, err := client.Do(req)
+	resp, err := doIntercepted(client, interceptors, req)
 	if err != nil {
 		return err
 	}
@@ -425,81 +684,212 @@ func sendRequest(
 		return apiError
 	}
 
-	// Return a generic error.
+	// Return a rate limit error for 429s, an auth error for 401s and 403s, and a generic error
+	// for anything else.
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return newRateLimitError(resp, req, time.Now())
+	}
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return AuthError{RequestError{Status: resp.StatusCode, Request: req}}
+	}
 	return RequestError{Status: resp.StatusCode, Request: req}
 }
 
-const jobsEndpoint = "https://clocktick.dev/api/v1/jobs"
+// DefaultBaseURL is the base URL of the Clocktick API used unless WithBaseURL is specified.
+const DefaultBaseURL = "https://clocktick.dev/api/v1"
+
+const jobsPath = "/jobs"
 
 // ScheduleJob is used to schedule a job in the server.
 func (s *Server) ScheduleJob(
 	ctx context.Context, route string, props ScheduleJobPropertiesBuilder,
 	args ...any,
 ) (JobCreationResponse, error) {
+	if s.local != nil {
+		return s.scheduleLocal(ctx, route, props, args, nil)
+	}
+	ctx, span := s.tracer.Start(ctx, "clocktick.schedule", "route", route)
+	id, body, err := s.prepareJob(ctx, route, props, args, nil)
+	if err != nil {
+		span.End(err)
+		return JobCreationResponse{}, err
+	}
+	if s.buffer != nil {
+		// The caller is told the job is scheduled before it is sent, so it always has a key
+		// in order that its batch can be retried.
+		if body.idempotencyKey == "" {
+			if body.idempotencyKey, err = s.newIdempotencyKey(); err != nil {
+				span.End(err)
+				return JobCreationResponse{}, err
+			}
+		}
+		err = s.buffer.add(bufferedJob{route: route, id: id, body: body})
+		span.End(err)
+		return JobCreationResponse{JobID: id}, err
+	}
+	s.incr(counterScheduleCalls)
+	res, err := s.submitJob(ctx, id, body)
+	span.End(err)
+	return res, err
+}
+
+// Validates, encodes, and encrypts the job so that it is ready to be sent to the API. next
+// holds the workflow steps to schedule once the job succeeds, if any.
+func (s *Server) prepareJob(
+	ctx context.Context, route string, props ScheduleJobPropertiesBuilder, args []any,
+	next []workflowStep,
+) (id string, body createJobSkeleton, err error) {
 	// Check if the route exists in the server.
 	r, ok := s.funcMap[route]
 	if !ok {
-		return JobCreationResponse{}, errors.New("route not found")
+		return "", body, fmt.Errorf("%w: %s", ErrRouteNotFound, route)
+	}
+
+	props = s.anchorProps(props)
+
+	// Validate the schedule, including the stricter checks if strict mode is on.
+	if err = validateProps(props, s.now(), s.strict); err != nil {
+		return "", body, err
+	}
+
+	// Check the argument count.
+	if r.numArgs != len(args) {
+		return "", body, argumentMismatch(r.numArgs, len(args))
+	}
+
+	// Marshal the arguments and any baggage into msgpack.
+	raws, err := encodeArgs(args)
+	if err != nil {
+		return "", body, err
+	}
+	if err = r.checkArgs(raws); err != nil {
+		return "", body, err
+	}
+	b, err := s.packPayload(ctx, payloadEnvelope{
+		Args: raws, Baggage: s.collectBaggage(ctx), Trace: s.traceCarrier(ctx), Next: next,
+		Version: r.version(),
+	})
+	if err != nil {
+		return "", body, err
 	}
 
-	// Get the endpoint ID.
+	// Build the skeleton and check the recurring interval.
+	id, body = props.buildSkeleton()
+	if err = s.checkRecurringInterval(props, body.RunEvery); err != nil {
+		return "", body, err
+	}
+
+	// Encrypt the data and fill in the rest of the body.
+	body, err = s.sealJob(ctx, body, route, s.routeEndpoint(r), b)
+	if err != nil {
+		return "", body, err
+	}
+
+	// Generate the idempotency key now so that it is the same however the job is sent.
+	if body.idempotencyKey, err = s.idempotencyKey(body); err != nil {
+		return "", body, err
+	}
+	return id, body, nil
+}
+
+// Gets the endpoint ID a job for the route is sent to.
+func (s *Server) routeEndpoint(r funcOpts) string {
 	endpointId := s.defaultEndpointId
+	var c *canary
 	for _, opt := range r.a {
 		if opt.customEndpointId != nil {
 			endpointId = *opt.customEndpointId
 		}
+		if opt.canary != nil {
+			c = opt.canary
+		}
 	}
+	if c != nil && c.pick() {
+		endpointId = c.endpointId
+	}
+	return endpointId
+}
 
-	// Get the function.
-	f := r.f
-	reflectValue := reflect.ValueOf(f)
-
-	// Get the argument count.
-	argumentCount := reflectValue.Type().NumIn()
-	if argumentCount-1 != len(args) {
-		return JobCreationResponse{}, errors.New("argument count mismatch")
+// Sends a prepared job to the API.
+func (c *Client) submitJob(
+	ctx context.Context, id string, body createJobSkeleton,
+) (JobCreationResponse, error) {
+	reqUrl := c.baseURL + jobsPath
+	if id != "" {
+		reqUrl += "/" + url.PathEscape(id)
 	}
 
-	// Marshal the arguments into msgpack.
-	b, err := msgpack.Marshal(args)
+	// Creating a job is safe to retry if it has a custom ID or an idempotency key, since the
+	// API will not create it twice.
+	var header http.Header
+	key, err := c.idempotencyKey(body)
 	if err != nil {
 		return JobCreationResponse{}, err
 	}
-
-	// Encrypt the data and send it on.
-	encryptedData := s.encrypt(b)
-	id, body := props.buildSkeleton()
-	body.EndpointID = endpointId
-	body.EncryptedData = encryptedData
-	body.JobType = route
-	reqUrl := jobsEndpoint
-	if id != "" {
-		reqUrl += "/" + url.PathEscape(id)
+	if key != "" {
+		header = http.Header{"Idempotency-Key": {key}}
 	}
 	respBody := JobCreationResponse{}
-	err = sendRequest(
-		ctx, s.client, s.apiKey, reqUrl, "POST", body, &respBody,
-	)
-	return respBody, err
+	err = c.sendRetrying(ctx, id != "" || key != "", "POST", reqUrl, header, body, &respBody)
+	return respBody, conflictError(err)
 }
 
-// DeleteJob is used to delete a job with the SDK.
+// DeleteJob is used to delete a job with the SDK. The HTTP client is taken from the
+// "http.Client" value of the context, and the default base URL is always used.
+//
+// Deprecated: Use Server.DeleteJob or Client.DeleteJob, which use the configured HTTP client,
+// base URL, retries, and circuit breaker.
 func DeleteJob(ctx context.Context, apiKey string, jobId string) error {
 	client, ok := ctx.Value("http.Client").(*http.Client)
 	if !ok {
-		client = http.DefaultClient
+		client = defaultClient
 	}
 	if jobId == "" {
 		return errors.New("job ID is required")
 	}
-	reqUrl := jobsEndpoint + "/" + url.PathEscape(jobId)
-	err := sendRequest(ctx, client, apiKey, reqUrl, "DELETE", nil, nil)
+	reqUrl := DefaultBaseURL + jobsPath + "/" + url.PathEscape(jobId)
+	err := sendRequest(ctx, client, apiKey, reqUrl, "DELETE", nil, nil, nil, nil)
 	return err
 }
 
+// HeaderPrefix is the prefix the custom headers attached to a job are delivered with.
+const HeaderPrefix = "X-Clocktick-Header-"
+
+type deliveryHeadersKey struct{}
+
+// DeliveryHeaders is used to get the custom headers the job was scheduled with from the
+// context passed to a handler. The keys have the header prefix removed and are in their
+// canonical HTTP form. Note that these headers are not covered by the signature.
+func DeliveryHeaders(ctx context.Context) map[string]string {
+	headers, _ := ctx.Value(deliveryHeadersKey{}).(map[string]string)
+	return headers
+}
+
+// Gets the custom headers from the inbound request.
+func getDeliveryHeaders(h http.Header) map[string]string {
+	var headers map[string]string
+	for k, v := range h {
+		if len(v) == 0 || !strings.HasPrefix(k, HeaderPrefix) {
+			continue
+		}
+		if headers == nil {
+			headers = make(map[string]string)
+		}
+		headers[strings.TrimPrefix(k, HeaderPrefix)] = v[0]
+	}
+	return headers
+}
+
 type inboundData struct {
 	Type          string `json:"type"`
+	JobID         string `json:"job_id"`
 	EncryptedData string `json:"encrypted_data"`
+
+	// Sent by newer versions of the platform.
+	CustomID    string     `json:"custom_id"`
+	EndpointID  string     `json:"endpoint_id"`
+	ScheduledAt *time.Time `json:"scheduled_at"`
+	Attempt     int        `json:"attempt"`
 }
 
 func panicCondom(f func()) (val any) {
@@ -512,98 +902,344 @@ func panicCondom(f func()) (val any) {
 	return
 }
 
+// Handler is used to get the handler for the delivery endpoint, for mounting the server in a
+// router. Unlike the server itself, the handler rejects methods other than POST with a 405.
+func (s *Server) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.ServeHTTP(w, r)
+	})
+}
+
 // ServeHTTP is used to serve the HTTP requests to the server.
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	// Validate the X-Signature-Ed25519 and X-Signature-Timestamp headers.
+	start := time.Now()
+	d := s.serve(w, r)
+	s.logAccess(d, time.Since(start))
+}
+
+// Handles the delivery, returning what happened to it.
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) (d delivery) {
+	s.incr(counterDeliveries)
+	s.metrics.DeliveryReceived()
+	d.status = http.StatusOK
+	d.outcome = outcomeOK
+	s.debugDelivery(r)
+
+	// Validate the signature and X-Signature-Timestamp headers.
 	tsHeader := r.Header.Get("X-Signature-Timestamp")
-	sigHeader := r.Header.Get("X-Signature-Ed25519")
+	sigHeader := r.Header.Get(s.signatureHeader())
 	if tsHeader == "" || sigHeader == "" {
-		http.Error(w, "missing headers", http.StatusBadRequest)
+		s.logger.Warn("rejected delivery", "reason", "missing signature headers")
+		s.handleError(r.Context(), "", errors.New("missing signature headers"))
+		d.fail(w, "missing headers", http.StatusBadRequest, outcomeRejected)
 		return
 	}
 
 	// Decode the signature from hex.
 	sig, err := hex.DecodeString(sigHeader)
 	if err != nil {
-		http.Error(w, "failed to decode signature", http.StatusBadRequest)
+		s.logger.Warn("rejected delivery", "reason", "signature is not valid hex", "error", err)
+		s.handleError(r.Context(), "", fmt.Errorf("failed to decode signature: %w", err))
+		d.fail(w, "failed to decode signature", http.StatusBadRequest, outcomeRejected)
 		return
 	}
 
 	// Read the data.
-	b, err := io.ReadAll(r.Body)
+	b, err := s.readBody(w, r)
+	var tooLarge *BodyTooLargeError
+	if errors.As(err, &tooLarge) {
+		s.logger.Warn("rejected delivery", "reason", "body is too large", "limit", tooLarge.Limit)
+		s.handleError(r.Context(), "", err)
+		d.fail(w, tooLarge.Error(), http.StatusRequestEntityTooLarge, outcomeRejected)
+		return
+	}
 	if err != nil {
-		http.Error(w, "failed to read body", http.StatusInternalServerError)
+		s.logger.Error("failed to read delivery body", "error", err)
+		s.handleError(r.Context(), "", fmt.Errorf("failed to read body: %w", err))
+		d.fail(w, "failed to read body", http.StatusInternalServerError, outcomeRejected)
+		return
 	}
+	d.payloadSize = len(b)
+	s.debugDeliveryBody(b)
 
 	// Verify the signature.
 	dataToVerify := make([]byte, len(tsHeader)+len(b))
 	copy(dataToVerify, tsHeader)
 	copy(dataToVerify[len(tsHeader):], b)
-	if !ed25519.Verify(s.publicKey, dataToVerify, sig) {
-		http.Error(w, "failed to verify signature", http.StatusUnauthorized)
+	if !s.verifySignature(dataToVerify, sig) {
+		s.incr(counterVerificationFailures)
+		s.metrics.SignatureFailed()
+		s.logger.Warn("rejected delivery", "reason", "signature verification failed")
+		s.handleError(r.Context(), "", errors.New("failed to verify signature"))
+		d.fail(w, "failed to verify signature", http.StatusUnauthorized, outcomeRejected)
 		return
 	}
 
-	// Check if the request is outdated.
+	// Check if the request is outdated or too far in the future.
 	ts, err := strconv.ParseInt(tsHeader, 10, 64)
 	if err != nil {
-		http.Error(w, "failed to parse timestamp", http.StatusBadRequest)
+		s.logger.Warn("rejected delivery", "reason", "timestamp is not valid", "error", err)
+		s.handleError(r.Context(), "", fmt.Errorf("failed to parse timestamp: %w", err))
+		d.fail(w, "failed to parse timestamp", http.StatusBadRequest, outcomeRejected)
 		return
 	}
-	if time.Now().Unix()-ts > 5*60 {
-		http.Error(w, "request is outdated", http.StatusUnauthorized)
+	if reason := s.checkTimestamp(time.Unix(ts, 0), time.Now()); reason != "" {
+		s.incr(counterVerificationFailures)
+		s.metrics.SignatureFailed()
+		s.logger.Warn("rejected delivery", "reason", reason, "timestamp", ts)
+		s.handleError(r.Context(), "", errors.New(reason))
+		d.fail(w, reason, http.StatusUnauthorized, outcomeRejected)
 		return
 	}
+	d.verified = true
+
+	// Reject the delivery if this exact request has been accepted before.
+	if s.replay != nil {
+		// The signature is re-encoded so that changing the case of the hex does not get past.
+		// The key must outlive the timestamp, which can be accepted until maxSkew+maxAge from now.
+		maxAge, maxSkew := s.timestampWindow()
+		seen, err := s.replay.Seen(r.Context(), hex.EncodeToString(sig), maxAge+maxSkew)
+		if err != nil {
+			s.logger.Error("failed to check replay store", "error", err)
+			d.fail(w, "failed to check replay store", http.StatusServiceUnavailable, outcomeRejected)
+			return
+		}
+		if seen {
+			s.logger.Warn("rejected delivery", "reason", "replayed request")
+			d.fail(w, "request has already been handled", http.StatusConflict, outcomeRejected)
+			return
+		}
+	}
 
 	// Unmarshal the data.
 	var data inboundData
 	err = json.Unmarshal(b, &data)
 	if err != nil {
-		http.Error(w, "failed to unmarshal data", http.StatusBadRequest)
+		s.logger.Warn("failed to unmarshal delivery", "error", err)
+		s.handleError(r.Context(), "", fmt.Errorf("failed to unmarshal data: %w", err))
+		d.fail(w, "failed to unmarshal data", http.StatusBadRequest, outcomeDecodeFailed)
 		return
 	}
+	d.route = data.Type
+	d.jobID = data.JobID
 
 	// Find the route.
 	route, ok := s.funcMap[data.Type]
+	if !ok && s.fallback != nil {
+		// Pass the delivery on with the body we have already read.
+		r.Body = io.NopCloser(bytes.NewReader(b))
+		r.ContentLength = int64(len(b))
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		s.fallback.ServeHTTP(sw, r)
+		d.status = sw.status
+		d.outcome = outcomeForwarded
+		return
+	}
 	if !ok {
-		http.Error(w, "route not found", http.StatusNotFound)
+		s.logger.Warn("route not found", "route", data.Type, "job_id", data.JobID)
+		d.fail(w, "route not found", http.StatusNotFound, outcomeRouteNotFound)
 		return
 	}
 
-	// Decrypt the data.
-	decryptedData, err := s.decrypt(data.EncryptedData)
+	// Hold a slot for the route whilst the job runs if it has a concurrency limit. The slot is
+	// handed to the worker pool along with the job when async execution is on.
+	var release func()
+	if limit := route.concurrencyLimit(); limit != nil {
+		if !limit.acquire() {
+			s.logger.Warn(
+				"route is at its concurrency limit", "route", data.Type, "job_id", data.JobID,
+			)
+			limit.reject(w, &d)
+			return
+		}
+		release = limit.release
+		defer func() {
+			if release != nil {
+				release()
+			}
+		}()
+	}
+
+	// Decrypt and decode the data.
+	env, err := s.openPayload(r.Context(), data.EncryptedData)
 	if err != nil {
-		http.Error(w, "failed to decrypt data", http.StatusInternalServerError)
+		if errors.Is(err, errDecryptFailed) {
+			s.metrics.DecryptFailed(data.Type)
+		}
+		s.logger.Error(
+			"failed to open payload", "route", data.Type, "job_id", data.JobID, "error", err,
+		)
+		s.handleError(r.Context(), data.Type, err)
+		d.fail(w, err.Error(), http.StatusInternalServerError, outcomeDecodeFailed)
+		return
+	}
+	if s.chaos.failDecrypt() {
+		s.metrics.DecryptFailed(data.Type)
+		d.fail(w, "failed to decrypt data (injected)", http.StatusInternalServerError, outcomeChaos)
+		return
+	}
+
+	// Run the version of the route the job was scheduled against, or migrate the arguments to
+	// the highest version if it has not been added.
+	route, raws, err := s.resolveVersion(data.Type, route, env)
+	if errors.Is(err, ErrRouteNotFound) {
+		s.logger.Warn(
+			"route version not found",
+			"route", data.Type, "job_id", data.JobID, "version", env.Version,
+		)
+		s.handleError(r.Context(), data.Type, err)
+		d.fail(w, "route version not found", http.StatusNotFound, outcomeRouteNotFound)
 		return
 	}
-	var raws []msgpack.RawMessage
-	err = msgpack.Unmarshal(decryptedData, &raws)
 	if err != nil {
-		http.Error(w, "failed to unmarshal encrypted data", http.StatusInternalServerError)
+		s.logger.Warn("failed to migrate arguments", "route", data.Type, "error", err)
+		s.handleError(r.Context(), data.Type, err)
+		d.fail(w, "failed to migrate arguments", http.StatusBadRequest, outcomeDecodeFailed)
 		return
 	}
 
-	// Get the function.
-	f := route.f
-	reflectValue := reflect.ValueOf(f)
-	if reflectValue.Type().NumIn()-1 != len(raws) {
-		http.Error(w, "argument count mismatch", http.StatusBadRequest)
+	// Check the argument count, fitting the arguments to the route if its policy allows.
+	if route.numArgs != len(raws) {
+		fitted, ok := route.fitArgs(raws)
+		s.logger.Warn(
+			"argument count mismatch",
+			"route", data.Type, "expected", route.numArgs, "got", len(raws),
+			"policy", route.argumentMismatch().String(), "accepted", ok,
+		)
+		if !ok {
+			s.handleError(r.Context(), data.Type, argumentMismatch(route.numArgs, len(raws)))
+			d.fail(w, "argument count mismatch", http.StatusBadRequest, outcomeDecodeFailed)
+			return
+		}
+		raws = fitted
+	}
+
+	// Build the context for the job.
+	ctx := s.restoreBaggage(r.Context(), env.Baggage)
+	jc := s.deliveryJobContext(r.Context(), data, time.Unix(ts, 0))
+	ctx = context.WithValue(ctx, jobContextKey{}, jc)
+	if headers := getDeliveryHeaders(r.Header); headers != nil {
+		ctx = context.WithValue(ctx, deliveryHeadersKey{}, headers)
+	}
+
+	// Inject a delay if chaos is enabled.
+	s.chaos.delay()
+
+	// Hand the job to the worker pool if async execution is on.
+	inv := &Invocation{Route: data.Type, JobID: data.JobID, Args: raws}
+	if s.async != nil {
+		s.queueJob(ctx, w, &d, env, route, inv, release)
+		release = nil
+		return
+	}
+
+	// Call the function through the middleware chain with the context and the arguments.
+	panicedValue, handlerErr := s.execute(ctx, env.Trace, route, inv)
+	if panicedValue != nil {
+		if s.jobFailed(ctx, inv, fmt.Errorf("panic: %v", panicedValue), true) {
+			d.deadLettered(w)
+			return
+		}
+		d.fail(w, "panic", http.StatusInternalServerError, outcomePanic)
 		return
 	}
+	if handlerErr != nil {
+		var argErr *ArgumentError
+		if errors.As(handlerErr, &argErr) {
+			s.logger.Warn("failed to decode argument", "route", data.Type, "error", handlerErr)
+			s.handleError(ctx, data.Type, handlerErr)
+			if s.jobFailed(ctx, inv, handlerErr, true) {
+				d.deadLettered(w)
+				return
+			}
+			d.fail(w, "failed to decode argument", http.StatusBadRequest, outcomeDecodeFailed)
+			return
+		}
+		s.logger.Error(
+			"handler returned an error", "route", data.Type, "job_id", data.JobID, "error", handlerErr,
+		)
+		s.handleError(ctx, data.Type, handlerErr)
+		s.reportError(ctx, data.Type, handlerErr)
+		if s.jobFailed(ctx, inv, handlerErr, false) {
+			d.deadLettered(w)
+			return
+		}
+
+		// Respond with a retryable status so the platform re-delivers the job.
+		var retryErr *RetryError
+		if errors.As(handlerErr, &retryErr) && retryErr.After > 0 {
+			w.Header().Set("Retry-After", retryAfterSeconds(retryErr.After))
+		}
+		d.fail(w, "handler error", http.StatusServiceUnavailable, outcomeHandlerError)
+		return
+	}
+	if s.chaos.failResponse() {
+		d.fail(w, "injected error", http.StatusServiceUnavailable, outcomeChaos)
+		return
+	}
+
+	// Schedule the next step of the workflow, failing the delivery if it cannot be so that the
+	// job is re-delivered.
+	if len(env.Next) != 0 {
+		if err := s.continueWorkflow(ctx, data.JobID, env.Next); err != nil {
+			s.logger.Error(
+				"failed to schedule next workflow step",
+				"route", data.Type, "job_id", data.JobID, "error", err,
+			)
+			s.handleError(ctx, data.Type, fmt.Errorf("failed to schedule next workflow step: %w", err))
+			d.fail(w, "failed to schedule next step", http.StatusServiceUnavailable, outcomeHandlerError)
+			return
+		}
+	}
+	s.jobSucceeded(data.JobID)
+
+	// Send the result of the job back to the platform if the handler returned one.
+	if inv.Result != nil {
+		b, err := json.Marshal(jobResultBody{Result: inv.Result})
+		if err != nil {
+			s.logger.Error("failed to marshal job result", "route", data.Type, "error", err)
+			d.fail(w, "failed to marshal result", http.StatusInternalServerError, outcomeHandlerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(b)
+	}
+	return
+}
 
-	// Call the function with the context and the arguments.
-	panicedValue := panicCondom(func() {
-		args := make([]reflect.Value, len(raws)+1)
-		args[0] = reflect.ValueOf(r.Context())
-		for i, raw := range raws {
-			args[i+1] = reflect.ValueOf(raw)
+// Runs the job through the middleware chain within a span linked to the span it was scheduled
+// in, returning the value if it panicked or the error of the handler.
+func (s *Server) execute(
+	ctx context.Context, trace map[string]string, route funcOpts, inv *Invocation,
+) (panicedValue any, handlerErr error) {
+	ctx = s.tracer.Extract(ctx, trace)
+	ctx, span := s.tracer.Start(ctx, "clocktick.deliver", "route", inv.Route, "job_id", inv.JobID)
+	h := s.chain(route)
+	start := time.Now()
+	panicedValue, stack := panicCondomStack(func() {
+		handlerErr = h(ctx, inv)
+		if handlerErr == nil && s.chaos.duplicate() {
+			handlerErr = h(ctx, inv)
 		}
-		reflectValue.Call(args)
 	})
+	s.metrics.JobHandled(inv.Route, panicedValue == nil && handlerErr == nil, time.Since(start))
 	if panicedValue != nil {
-		s.panicHandler(panicedValue)
-		http.Error(w, "panic", http.StatusInternalServerError)
+		span.End(fmt.Errorf("panic: %v", panicedValue))
+		s.incr(counterPanics)
+		s.logger.Error(
+			"handler panicked", "route", inv.Route, "job_id", inv.JobID, "panic", panicedValue,
+		)
+		s.handlePanic(ctx, inv.Route, panicedValue, stack)
+		s.reportPanic(ctx, inv.Route, panicedValue, stack)
+		return panicedValue, nil
 	}
+	span.End(handlerErr)
+	return nil, handlerErr
 }
 
 var _ http.Handler = &Server{}
@@ -1 +1,157 @@
 package sdk_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"go.clocktick.dev/sdk"
+	"go.clocktick.dev/sdk/sdktest"
+)
+
+const testEncryptionKey = "test-encryption-key"
+
+// Defines a request received by the recording API.
+type recordedRequest struct {
+	Method string
+	Path   string
+	Header http.Header
+	Body   []byte
+}
+
+// Decodes the JSON body of the request into a map.
+func (r recordedRequest) json(t *testing.T) map[string]any {
+	t.Helper()
+	var m map[string]any
+	if err := json.Unmarshal(r.Body, &m); err != nil {
+		t.Fatalf("body is not a JSON object: %v: %s", err, r.Body)
+	}
+	return m
+}
+
+// Defines a fake API which records the requests it receives. It replies with the result of
+// reply if it is set, and otherwise with a created job.
+type recordingAPI struct {
+	URL string
+
+	mu       sync.Mutex
+	requests []recordedRequest
+	reply    func(r recordedRequest) (int, string)
+}
+
+// Starts a recording API which is closed when the test ends.
+func newRecordingAPI(t *testing.T) *recordingAPI {
+	t.Helper()
+	a := &recordingAPI{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		req := recordedRequest{Method: r.Method, Path: r.URL.Path, Header: r.Header, Body: body}
+		a.mu.Lock()
+		a.requests = append(a.requests, req)
+		reply := a.reply
+		a.mu.Unlock()
+
+		status, resp := http.StatusOK, `{"job_id":"job_1"}`
+		if reply != nil {
+			status, resp = reply(req)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = io.WriteString(w, resp)
+	}))
+	t.Cleanup(srv.Close)
+	a.URL = srv.URL
+	return a
+}
+
+// Sets the function the API replies with.
+func (a *recordingAPI) setReply(f func(r recordedRequest) (int, string)) {
+	a.mu.Lock()
+	a.reply = f
+	a.mu.Unlock()
+}
+
+// Gets the requests the API has received.
+func (a *recordingAPI) received() []recordedRequest {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]recordedRequest(nil), a.requests...)
+}
+
+// Gets the last request the API received, failing the test if there is none.
+func (a *recordingAPI) last(t *testing.T) recordedRequest {
+	t.Helper()
+	reqs := a.received()
+	if len(reqs) == 0 {
+		t.Fatal("API received no requests")
+	}
+	return reqs[len(reqs)-1]
+}
+
+// Creates a server with a new keypair which sends its API requests to the URL specified, and
+// a signer for building deliveries to it.
+func newTestServer(t *testing.T, baseURL string, opts ...sdk.ServerOption) (*sdk.Server, *sdktest.Signer) {
+	t.Helper()
+	pub, priv, err := sdktest.GenerateKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newTestServerWithKey(t, baseURL, pub, opts...)
+	signer, err := sdktest.NewSigner(priv, testEncryptionKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, signer
+}
+
+// Creates a server with the public key specified which sends its API requests to the URL.
+func newTestServerWithKey(
+	t *testing.T, baseURL, publicKey string, opts ...sdk.ServerOption,
+) *sdk.Server {
+	t.Helper()
+	opts = append([]sdk.ServerOption{sdk.WithBaseURL(baseURL)}, opts...)
+	s, err := sdk.NewServerE("api-key", testEncryptionKey, publicKey, "endpoint", opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// Gets a private key which is not the one any test server was created with.
+func otherPrivateKey(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+	_, priv, err := sdktest.GenerateKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return priv
+}
+
+// Sends the request to the server, returning the response.
+func serve(s http.Handler, req *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	return w
+}
+
+// Adds a route which takes a single string and records the arguments it was called with.
+func addRecordingRoute(s *sdk.Server, route string, err error) (sdk.Route1[string], func() []string) {
+	var mu sync.Mutex
+	var calls []string
+	r := sdk.AddRoute1(s, route, func(_ context.Context, v string) error {
+		mu.Lock()
+		calls = append(calls, v)
+		mu.Unlock()
+		return err
+	})
+	return r, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), calls...)
+	}
+}
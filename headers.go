@@ -0,0 +1,63 @@
+package sdk
+
+import "net/http"
+
+// WithUserAgent is used to set the User-Agent header sent with API requests.
+func WithUserAgent(userAgent string) ServerOption {
+	return func(s *Server) {
+		s.setRequestHeader("User-Agent", userAgent)
+	}
+}
+
+// WithClientUserAgent is used to set the User-Agent header sent with API requests.
+func WithClientUserAgent(userAgent string) ClientOption {
+	return func(c *Client) {
+		c.setRequestHeader("User-Agent", userAgent)
+	}
+}
+
+// WithRequestHeader is used to add a header to every API request, such as an identifier
+// required by an egress gateway. The Authorization header cannot be replaced, and headers
+// set for a single request, such as Idempotency-Key, take precedence.
+func WithRequestHeader(key, value string) ServerOption {
+	return func(s *Server) {
+		s.addRequestHeader(key, value)
+	}
+}
+
+// WithClientRequestHeader is used to add a header to every API request, such as an identifier
+// required by an egress gateway. The Authorization header cannot be replaced, and headers
+// set for a single request, such as Idempotency-Key, take precedence.
+func WithClientRequestHeader(key, value string) ClientOption {
+	return func(c *Client) {
+		c.addRequestHeader(key, value)
+	}
+}
+
+// Sets a header sent with every request, replacing any values it already has.
+func (c *Client) setRequestHeader(key, value string) {
+	if c.headers == nil {
+		c.headers = http.Header{}
+	}
+	c.headers.Set(key, value)
+}
+
+// Adds a header sent with every request.
+func (c *Client) addRequestHeader(key, value string) {
+	if c.headers == nil {
+		c.headers = http.Header{}
+	}
+	c.headers.Add(key, value)
+}
+
+// Merges the headers for a single request over the headers sent with every request.
+func (c *Client) requestHeader(header http.Header) http.Header {
+	if len(c.headers) == 0 {
+		return header
+	}
+	merged := c.headers.Clone()
+	for k, v := range header {
+		merged[k] = v
+	}
+	return merged
+}
@@ -0,0 +1,53 @@
+package sdk
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// The shortest time between heartbeats sent to the API. Heartbeats sent sooner are skipped.
+const minHeartbeatInterval = 5 * time.Second
+
+// Sends heartbeats for a delivery which is still being handled.
+type heartbeat struct {
+	c       *Client
+	ctx     context.Context
+	jobID   string
+	attempt int
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+// Defines the body of a heartbeat request.
+type heartbeatBody struct {
+	Attempt int `json:"attempt"`
+}
+
+// Sends the heartbeat unless one was sent recently.
+func (h *heartbeat) send() error {
+	h.mu.Lock()
+	if time.Since(h.last) < minHeartbeatInterval {
+		h.mu.Unlock()
+		return nil
+	}
+	h.last = time.Now()
+	h.mu.Unlock()
+
+	reqUrl := h.c.baseURL + jobsPath + "/" + url.PathEscape(h.jobID) + "/heartbeat"
+	return h.c.sendIdempotent(h.ctx, "POST", reqUrl, heartbeatBody{Attempt: h.attempt}, nil)
+}
+
+// Heartbeat is used to tell the platform that the handler is still working on the job, which
+// extends the delivery timeout so that long running jobs are not re-delivered whilst they run.
+// Call it periodically, such as between batches of work. Calls made within a few seconds of
+// the last are skipped, so it is cheap to call often. It does nothing in local mode or with
+// WithAsyncExecution, since the delivery has already been acknowledged.
+func (jc JobContext) Heartbeat() error {
+	if jc.heartbeat == nil {
+		return nil
+	}
+	return jc.heartbeat.send()
+}
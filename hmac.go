@@ -0,0 +1,38 @@
+package sdk
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+)
+
+// HMACSignatureHeader is the header deliveries are signed in when WithHMACSecret is used. It
+// holds the hex encoded HMAC-SHA256 of the timestamp followed by the body.
+const HMACSignatureHeader = "X-Signature-HMAC-SHA256"
+
+// WithHMACSecret is used to verify deliveries with HMAC-SHA256 and the shared secret
+// specified instead of Ed25519, for environments which cannot manage Ed25519 keys. The
+// signature is read from HMACSignatureHeader, and the public key passed to NewServer can be
+// empty. Ed25519 signatures are not accepted when this is set. Panics if the secret is empty.
+func WithHMACSecret(secret string) ServerOption {
+	if secret == "" {
+		panic("secret must not be empty")
+	}
+	return func(s *Server) {
+		s.hmacSecret = []byte(secret)
+	}
+}
+
+// Gets the header the signature of a delivery is in.
+func (s *Server) signatureHeader() string {
+	if s.hmacSecret != nil {
+		return HMACSignatureHeader
+	}
+	return "X-Signature-Ed25519"
+}
+
+// Checks the HMAC-SHA256 of the data matches the signature.
+func (s *Server) verifyHMAC(data, sig []byte) bool {
+	mac := hmac.New(sha256.New, s.hmacSecret)
+	mac.Write(data)
+	return hmac.Equal(mac.Sum(nil), sig)
+}
@@ -0,0 +1,40 @@
+package sdk
+
+import (
+	"encoding/hex"
+	"io"
+)
+
+// WithIdempotencyKeys is used to generate an idempotency key for every job the server creates
+// without one, so that job creation can be retried safely after a transient error.
+func WithIdempotencyKeys() ServerOption {
+	return func(s *Server) {
+		s.Client.autoIdemKeys = true
+	}
+}
+
+// WithClientIdempotencyKeys is used to generate an idempotency key for every job the client
+// creates without one, so that job creation can be retried safely after a transient error.
+func WithClientIdempotencyKeys() ClientOption {
+	return func(c *Client) {
+		c.autoIdemKeys = true
+	}
+}
+
+// Generates a random idempotency key.
+func (c *Client) newIdempotencyKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := io.ReadFull(c.randomReader(), b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Gets the idempotency key the job is created with, generating one if the job has none and
+// keys are generated for every job.
+func (c *Client) idempotencyKey(body createJobSkeleton) (string, error) {
+	if body.idempotencyKey != "" || !c.autoIdemKeys {
+		return body.idempotencyKey, nil
+	}
+	return c.newIdempotencyKey()
+}
@@ -0,0 +1,55 @@
+package sdk
+
+import "net/http"
+
+// Interceptor is used to observe or change the requests sent to the API, such as to log them
+// for auditing, record metrics, sign them, or add headers. It is called for every attempt, so
+// a request which is retried passes through it more than once.
+type Interceptor interface {
+	// BeforeSend is called with the request once it is built, just before it is sent. The
+	// request can be changed. An error stops the request from being sent and is returned
+	// without being retried.
+	BeforeSend(req *http.Request) error
+
+	// AfterReceive is called with the response, or the error if there was none. The body of
+	// the response has not been read yet, so an interceptor which reads it must replace it.
+	AfterReceive(req *http.Request, resp *http.Response, err error)
+}
+
+// WithInterceptors is used to add interceptors to the requests the server sends to the API.
+// Interceptors added first are the outermost, so they see the request first and the
+// response last.
+func WithInterceptors(interceptors ...Interceptor) ServerOption {
+	return func(s *Server) {
+		s.interceptors = append(s.interceptors, interceptors...)
+	}
+}
+
+// WithClientInterceptors is used to add interceptors to the requests the client sends to the
+// API. Interceptors added first are the outermost, so they see the request first and the
+// response last.
+func WithClientInterceptors(interceptors ...Interceptor) ClientOption {
+	return func(c *Client) {
+		c.interceptors = append(c.interceptors, interceptors...)
+	}
+}
+
+// Sends the request through the interceptors.
+func doIntercepted(
+	client *http.Client, interceptors []Interceptor, req *http.Request,
+) (*http.Response, error) {
+	for i, ic := range interceptors {
+		if err := ic.BeforeSend(req); err != nil {
+			// Let the interceptors which saw the request know it failed.
+			for j := i - 1; j >= 0; j-- {
+				interceptors[j].AfterReceive(req, nil, err)
+			}
+			return nil, err
+		}
+	}
+	resp, err := client.Do(req)
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptors[i].AfterReceive(req, resp, err)
+	}
+	return resp, err
+}
@@ -0,0 +1,53 @@
+package sdk
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Picks a random offset below max, rounded down to the millisecond since that is the smallest
+// unit the API accepts.
+func jitterOffset(max time.Duration) time.Duration {
+	if max == 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(max))).Truncate(time.Millisecond)
+}
+
+// Adds the units of the deltas together.
+func (d Delta) plus(o Delta) Delta {
+	return Delta{
+		Years:        d.Years + o.Years,
+		Months:       d.Months + o.Months,
+		Days:         d.Days + o.Days,
+		Hours:        d.Hours + o.Hours,
+		Minutes:      d.Minutes + o.Minutes,
+		Seconds:      d.Seconds + o.Seconds,
+		Milliseconds: d.Milliseconds + o.Milliseconds,
+	}
+}
+
+// Jitter is used to delay the first run of the job by a random amount of time below max.
+// Since each run is scheduled from the one before, a recurring job keeps the offset, so jobs
+// created together are spread out rather than all running at the same moment. A new offset
+// is picked for every job scheduled with the builder, so it can be shared, such as with
+// ScheduleFanOut. Panics if max is negative.
+func (p FromNowPropertiesBuilder) Jitter(max time.Duration) FromNowPropertiesBuilder {
+	p.maxJitter = checkJitter(max)
+	return p
+}
+
+// Jitter is used to delay the first run of the job by a random amount of time below max. See
+// FromNowPropertiesBuilder.Jitter.
+func (p FromTimePropertiesBuilder) Jitter(max time.Duration) FromTimePropertiesBuilder {
+	p.maxJitter = checkJitter(max)
+	return p
+}
+
+// Panics if the maximum jitter is negative.
+func checkJitter(max time.Duration) time.Duration {
+	if max < 0 {
+		panic("jitter must not be negative")
+	}
+	return max
+}
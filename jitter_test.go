@@ -0,0 +1,51 @@
+package sdk_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"go.clocktick.dev/sdk"
+)
+
+func TestFanOutJobsGetTheirOwnJitter(t *testing.T) {
+	api := newRecordingAPI(t)
+	api.setReply(batchReply)
+	s, _ := newTestServer(t, api.URL)
+	addRecordingRoute(s, "email", nil)
+
+	argSets := make([][]any, 5)
+	for i := range argSets {
+		argSets[i] = []any{"user"}
+	}
+	for name, props := range map[string]sdk.ScheduleJobPropertiesBuilder{
+		"FromNow":  sdk.FromNow().Hours(1).Jitter(time.Hour),
+		"FromTime": sdk.FromTime(time.Now().Add(time.Hour)).Jitter(time.Hour),
+	} {
+		t.Run(name, func(t *testing.T) {
+			for i, res := range s.ScheduleFanOut(context.Background(), "email", props, argSets) {
+				if res.Err != nil {
+					t.Fatalf("job %d: %v", i, res.Err)
+				}
+			}
+			starts := map[string]bool{}
+			for _, job := range batchJobs(t, api.last(t)) {
+				b, _ := json.Marshal(job["start_from"])
+				starts[string(b)] = true
+			}
+			if len(starts) < 2 {
+				t.Errorf("all %d jobs start at %v, want different offsets", len(argSets), starts)
+			}
+		})
+	}
+}
+
+func TestJitterPanicsWhenNegative(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("negative jitter did not panic")
+		}
+	}()
+	sdk.FromNow().Hours(1).Jitter(-time.Second)
+}
@@ -0,0 +1,72 @@
+package sdk
+
+import (
+	"context"
+	"time"
+)
+
+// JobContext defines the details of the job a handler is running, which are available from
+// the context passed to the handler through JobFromContext.
+type JobContext struct {
+	// JobID is the ID of the job.
+	JobID string
+
+	// CustomID is the custom ID the job was scheduled with, if any.
+	CustomID string
+
+	// Route is the route the job was scheduled against.
+	Route string
+
+	// ScheduledAt is when the run was due. If the platform does not send it, the time the
+	// delivery was signed is used.
+	ScheduledAt time.Time
+
+	// Attempt is the delivery attempt of the run, starting at 1. If the platform does not
+	// send it, it is counted from the failures of the job seen by this server when
+	// WithDeadLetter is used, and is otherwise 1.
+	Attempt int
+
+	// EndpointID is the endpoint the job was delivered to, if known.
+	EndpointID string
+
+	heartbeat *heartbeat
+}
+
+type jobContextKey struct{}
+
+// JobFromContext is used to get the details of the job being run from the context passed to
+// a handler. Returns false if the context is not from a job.
+func JobFromContext(ctx context.Context) (JobContext, bool) {
+	jc, ok := ctx.Value(jobContextKey{}).(JobContext)
+	return jc, ok
+}
+
+// Builds the job context for a delivery.
+func (s *Server) deliveryJobContext(
+	ctx context.Context, data inboundData, signedAt time.Time,
+) JobContext {
+	jc := JobContext{
+		JobID:       data.JobID,
+		CustomID:    data.CustomID,
+		Route:       data.Type,
+		ScheduledAt: signedAt,
+		Attempt:     data.Attempt,
+		EndpointID:  data.EndpointID,
+	}
+	if data.ScheduledAt != nil {
+		jc.ScheduledAt = *data.ScheduledAt
+	}
+	if jc.EndpointID == "" {
+		jc.EndpointID = s.defaultEndpointId
+	}
+	if jc.Attempt == 0 {
+		jc.Attempt = 1
+		if s.deadLetters != nil {
+			jc.Attempt += s.deadLetters.failures(data.JobID)
+		}
+	}
+	if s.async == nil {
+		jc.heartbeat = &heartbeat{c: s.Client, ctx: ctx, jobID: data.JobID, attempt: jc.Attempt}
+	}
+	return jc
+}
@@ -0,0 +1,232 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// JobStatus defines the status of a job.
+type JobStatus string
+
+const (
+	// JobStatusScheduled is the status of a job which is waiting to run.
+	JobStatusScheduled JobStatus = "scheduled"
+
+	// JobStatusPaused is the status of a job which has been paused.
+	JobStatusPaused JobStatus = "paused"
+
+	// JobStatusCompleted is the status of a job which has run and will not run again.
+	JobStatusCompleted JobStatus = "completed"
+
+	// JobStatusFailed is the status of a job which failed to be delivered.
+	JobStatusFailed JobStatus = "failed"
+)
+
+// JobStart defines when a job returned by the API starts. Type is "delta", "datetime",
+// "cron", or "rrule", and decides which of the other fields are set.
+type JobStart struct {
+	Type string `json:"type"`
+	Delta
+	DateTime   time.Time `json:"datetime"`
+	Expression string    `json:"expression"`
+	Rule       string    `json:"rule"`
+}
+
+// Job defines the structure of a job returned by the API.
+type Job struct {
+	ID            string     `json:"id"`
+	CustomID      string     `json:"custom_id,omitempty"`
+	Route         string     `json:"job_type"`
+	EndpointID    string     `json:"endpoint_id"`
+	Status        JobStatus  `json:"status"`
+	StartFrom     JobStart   `json:"start_from"`
+	RunEvery      *Delta     `json:"run_every"`
+	NextRunAt     *time.Time `json:"next_run_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	EncryptedData string     `json:"encrypted_data"`
+
+	// Until and MaxRuns are the end conditions of a recurring job, if any.
+	Until   *time.Time `json:"until,omitempty"`
+	MaxRuns uint       `json:"max_runs,omitempty"`
+
+	// Tags and Metadata are the labels the job was scheduled with.
+	Tags     []string          `json:"tags,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// ListJobsOptions is used to filter and page through the jobs returned by ListJobs. Empty
+// fields are not filtered on.
+type ListJobsOptions struct {
+	Route        string
+	EndpointID   string
+	Status       JobStatus
+	Tag          string
+	CustomID     string
+	CreatedAfter time.Time
+
+	// Cursor is the NextCursor of the previous page.
+	Cursor string
+
+	// Limit is the maximum number of jobs in the page. If 0, the API default is used.
+	Limit int
+}
+
+// Builds the query string for the options.
+func (o ListJobsOptions) query() url.Values {
+	query := url.Values{}
+	if o.Route != "" {
+		query.Set("route", o.Route)
+	}
+	if o.EndpointID != "" {
+		query.Set("endpoint_id", o.EndpointID)
+	}
+	if o.Status != "" {
+		query.Set("status", string(o.Status))
+	}
+	if o.Tag != "" {
+		query.Set("tag", o.Tag)
+	}
+	if o.CustomID != "" {
+		query.Set("custom_id", o.CustomID)
+	}
+	if !o.CreatedAfter.IsZero() {
+		query.Set("created_after", o.CreatedAfter.UTC().Format(time.RFC3339))
+	}
+	if o.Cursor != "" {
+		query.Set("cursor", o.Cursor)
+	}
+	if o.Limit != 0 {
+		query.Set("limit", strconv.Itoa(o.Limit))
+	}
+	return query
+}
+
+// JobList defines a page of jobs returned by ListJobs.
+type JobList struct {
+	Jobs []Job `json:"jobs"`
+
+	// NextCursor is the cursor of the next page, or empty if this is the last page.
+	NextCursor string `json:"next_cursor"`
+}
+
+// ListJobs is used to get a page of the jobs matching the options specified.
+func (c *Client) ListJobs(ctx context.Context, opts ListJobsOptions) (JobList, error) {
+	reqUrl := c.baseURL + jobsPath
+	if query := opts.query(); len(query) != 0 {
+		reqUrl += "?" + query.Encode()
+	}
+	var list JobList
+	err := c.send(ctx, "GET", reqUrl, nil, &list)
+	return list, err
+}
+
+// GetJob is used to get the job with the ID specified.
+func (c *Client) GetJob(ctx context.Context, jobId string) (Job, error) {
+	if jobId == "" {
+		return Job{}, errors.New("job ID is required")
+	}
+	var job Job
+	reqUrl := c.baseURL + jobsPath + "/" + url.PathEscape(jobId)
+	err := c.send(ctx, "GET", reqUrl, nil, &job)
+	return job, err
+}
+
+type updateJobBody struct {
+	StartFrom     any                   `json:"start_from"`
+	RunEvery      *Delta                `json:"run_every"`
+	EncryptedData string                `json:"encrypted_data,omitempty"`
+	Headers       map[string]string     `json:"headers,omitempty"`
+	Timezone      string                `json:"timezone,omitempty"`
+	Until         string                `json:"until,omitempty"`
+	MaxRuns       uint                  `json:"max_runs,omitempty"`
+	Exclusions    []exclusionWindowJSON `json:"exclusions,omitempty"`
+	Tags          []string              `json:"tags,omitempty"`
+	Metadata      map[string]string     `json:"metadata,omitempty"`
+}
+
+// UpdateJob is used to change the schedule of an existing job in place, keeping its ID,
+// custom ID, and run history. If args are specified, the payload of the job is replaced as
+// well and they are checked against the job's route in the same way as ScheduleJob. The
+// custom ID of the properties is ignored. The tags and metadata of the job are only replaced
+// if the properties have any, so they are kept when only the schedule is changing.
+func (s *Server) UpdateJob(
+	ctx context.Context, jobId string, props ScheduleJobPropertiesBuilder, args ...any,
+) (Job, error) {
+	if jobId == "" {
+		return Job{}, errors.New("job ID is required")
+	}
+
+	var body createJobSkeleton
+	if len(args) == 0 {
+		// Only the schedule is changing.
+		props = s.anchorProps(props)
+		if err := validateProps(props, s.now(), s.strict); err != nil {
+			return Job{}, err
+		}
+		_, body = props.buildSkeleton()
+		if err := s.checkRecurringInterval(props, body.RunEvery); err != nil {
+			return Job{}, err
+		}
+	} else {
+		// Get the route of the job so the arguments can be checked and encrypted.
+		job, err := s.GetJob(ctx, jobId)
+		if err != nil {
+			return Job{}, err
+		}
+		_, body, err = s.prepareJob(ctx, job.Route, props, args, nil)
+		if err != nil {
+			return Job{}, err
+		}
+	}
+
+	var job Job
+	reqUrl := s.baseURL + jobsPath + "/" + url.PathEscape(jobId)
+	err := s.send(ctx, "PATCH", reqUrl, updateJobBody{
+		StartFrom:     body.StartFrom,
+		RunEvery:      body.RunEvery,
+		EncryptedData: body.EncryptedData,
+		Headers:       body.Headers,
+		Timezone:      body.Timezone,
+		Until:         body.Until,
+		MaxRuns:       body.MaxRuns,
+		Exclusions:    body.Exclusions,
+		Tags:          body.Tags,
+		Metadata:      body.Metadata,
+	}, &job)
+	return job, err
+}
+
+// Runs an action on the job with the ID specified.
+func (c *Client) jobAction(ctx context.Context, jobId, action string) error {
+	if jobId == "" {
+		return errors.New("job ID is required")
+	}
+	reqUrl := c.baseURL + jobsPath + "/" + url.PathEscape(jobId) + "/" + action
+	return c.sendIdempotent(ctx, "POST", reqUrl, nil, nil)
+}
+
+// PauseJob is used to stop a job from running until it is resumed. The schedule of the job is
+// kept, and any runs which would have happened whilst it was paused are skipped.
+func (c *Client) PauseJob(ctx context.Context, jobId string) error {
+	return c.jobAction(ctx, jobId, "pause")
+}
+
+// ResumeJob is used to resume a job which was paused with PauseJob.
+func (c *Client) ResumeJob(ctx context.Context, jobId string) error {
+	return c.jobAction(ctx, jobId, "resume")
+}
+
+// TriggerJob is used to make the platform deliver a job straight away without changing its
+// schedule, which is useful for running a job by hand when debugging. The next scheduled run
+// still happens as normal. Unlike the other actions, the request is not retried, since a retry
+// could run the job twice.
+func (c *Client) TriggerJob(ctx context.Context, jobId string) error {
+	if jobId == "" {
+		return errors.New("job ID is required")
+	}
+	reqUrl := c.baseURL + jobsPath + "/" + url.PathEscape(jobId) + "/trigger"
+	return c.send(ctx, "POST", reqUrl, nil, nil)
+}
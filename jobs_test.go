@@ -0,0 +1,118 @@
+package sdk_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.clocktick.dev/sdk"
+	"go.clocktick.dev/sdk/sdktest"
+)
+
+// Updates the job with the properties, returning the body of the PATCH request.
+func updateJobBody(t *testing.T, props sdk.ScheduleJobPropertiesBuilder) map[string]any {
+	t.Helper()
+	api := newRecordingAPI(t)
+	api.setReply(func(recordedRequest) (int, string) { return 200, `{"id":"job_1"}` })
+	s, _ := newTestServer(t, api.URL)
+	if _, err := s.UpdateJob(context.Background(), "job_1", props); err != nil {
+		t.Fatal(err)
+	}
+	req := api.last(t)
+	if req.Method != "PATCH" || req.Path != "/jobs/job_1" {
+		t.Fatalf("got %s %s, want PATCH /jobs/job_1", req.Method, req.Path)
+	}
+	return req.json(t)
+}
+
+func TestUpdateJobSendsSchedule(t *testing.T) {
+	start := time.Date(2030, 1, 1, 9, 0, 0, 0, time.UTC)
+	body := updateJobBody(t, sdk.FromTime(start).EveryDays(1).Header("X-Team", "billing").MaxRuns(3))
+
+	if got := body["start_from"].(map[string]any)["datetime"]; got != "2030-01-01T09:00:00Z" {
+		t.Errorf("start_from.datetime = %v", got)
+	}
+	if got := body["run_every"].(map[string]any)["days"]; got != float64(1) {
+		t.Errorf("run_every.days = %v, want 1", got)
+	}
+	if got := body["headers"].(map[string]any)["X-Team"]; got != "billing" {
+		t.Errorf("headers.X-Team = %v, want billing", got)
+	}
+	if got := body["max_runs"]; got != float64(3) {
+		t.Errorf("max_runs = %v, want 3", got)
+	}
+}
+
+func TestUpdateJobSendsTimezone(t *testing.T) {
+	start := time.Date(2030, 1, 1, 9, 0, 0, 0, time.UTC)
+	body := updateJobBody(t, sdk.FromTime(start).Timezone("Europe/London").EveryDays(1))
+	if got := body["timezone"]; got != "Europe/London" {
+		t.Errorf("timezone = %v, want Europe/London", got)
+	}
+}
+
+func TestUpdateJobSendsExclusions(t *testing.T) {
+	start := time.Date(2030, 1, 1, 9, 0, 0, 0, time.UTC)
+	body := updateJobBody(t, sdk.FromTime(start).EveryDays(1).Exclude(
+		sdk.ExcludeWeekends(), sdk.ExcludeTimeOfDay(22*time.Hour, 6*time.Hour),
+	))
+	windows, _ := body["exclusions"].([]any)
+	if len(windows) != 2 {
+		t.Fatalf("exclusions = %v, want 2 windows", body["exclusions"])
+	}
+	night := windows[1].(map[string]any)
+	if night["start"] != "22:00" || night["end"] != "06:00" {
+		t.Errorf("exclusions[1] = %v, want 22:00 to 06:00", night)
+	}
+}
+
+func TestUpdateJobSendsLabels(t *testing.T) {
+	start := time.Date(2030, 1, 1, 9, 0, 0, 0, time.UTC)
+	body := updateJobBody(t, sdk.FromTime(start).Tag("billing").Metadata("tenant", "acme"))
+	if tags, _ := body["tags"].([]any); len(tags) != 1 || tags[0] != "billing" {
+		t.Errorf("tags = %v, want [billing]", body["tags"])
+	}
+	if got := body["metadata"].(map[string]any)["tenant"]; got != "acme" {
+		t.Errorf("metadata.tenant = %v, want acme", got)
+	}
+
+	// Properties without labels leave them unchanged.
+	body = updateJobBody(t, sdk.FromTime(start))
+	if _, ok := body["tags"]; ok {
+		t.Errorf("tags were sent without being set: %v", body["tags"])
+	}
+	if _, ok := body["metadata"]; ok {
+		t.Errorf("metadata was sent without being set: %v", body["metadata"])
+	}
+}
+
+func TestUpdateJobKeepsLabelsInFakeAPI(t *testing.T) {
+	api := sdktest.NewAPI("")
+	defer api.Close()
+	pub, _, err := sdktest.GenerateKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newTestServerWithKey(t, api.URL, pub)
+	r, _ := addRecordingRoute(s, "email", nil)
+	ctx := context.Background()
+
+	res, err := r.Schedule(ctx, sdk.FromNow().Hours(1).Tag("billing"), "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	job, err := s.UpdateJob(ctx, res.JobID, sdk.FromNow().Hours(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(job.Tags) != 1 || job.Tags[0] != "billing" {
+		t.Errorf("tags after update = %v, want [billing]", job.Tags)
+	}
+	job, err = s.UpdateJob(ctx, res.JobID, sdk.FromNow().Hours(2).Tag("urgent"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(job.Tags) != 1 || job.Tags[0] != "urgent" {
+		t.Errorf("tags after retagging = %v, want [urgent]", job.Tags)
+	}
+}
@@ -0,0 +1,35 @@
+package sdk
+
+import "crypto/ed25519"
+
+// WithPublicKeys is used to accept deliveries signed by any of the hex encoded public keys
+// specified as well as the one passed to NewServer. This allows the platform to rotate its
+// signing key with an overlap period where both keys are valid.
+func WithPublicKeys(publicKeys ...string) ServerOption {
+	return func(s *Server) {
+		for _, k := range publicKeys {
+			pub, err := decodePublicKey(k)
+			if err != nil {
+				if s.optErr == nil {
+					s.optErr = err
+				}
+				return
+			}
+			s.publicKeys = append(s.publicKeys, pub)
+		}
+	}
+}
+
+// Checks if the signature was made by any of the public keys, or with the HMAC secret if one
+// is set.
+func (s *Server) verifySignature(data, sig []byte) bool {
+	if s.hmacSecret != nil {
+		return s.verifyHMAC(data, sig)
+	}
+	for _, pub := range s.publicKeys {
+		if ed25519.Verify(pub, data, sig) {
+			return true
+		}
+	}
+	return false
+}
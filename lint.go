@@ -0,0 +1,86 @@
+package sdk
+
+import (
+	"fmt"
+	"time"
+)
+
+// LintCode is used to identify the kind of a lint warning.
+type LintCode string
+
+const (
+	// LintZeroRecurringDelta is returned when a recurring job has a zero delta.
+	LintZeroRecurringDelta LintCode = "zero_recurring_delta"
+
+	// LintMonthEndAnchor is returned when a job recurring by months or years is anchored on a
+	// day that does not exist in every month.
+	LintMonthEndAnchor LintCode = "month_end_anchor"
+
+	// LintEndsBeforeStart is returned when a recurring job is set to stop before it first runs.
+	LintEndsBeforeStart LintCode = "ends_before_start"
+)
+
+// LintWarning defines a problem found with a schedule by LintSchedule.
+type LintWarning struct {
+	Code    LintCode `json:"code"`
+	Message string   `json:"message"`
+}
+
+// LintSchedule is used to check a schedule for patterns which are valid but likely to not do
+// what was intended. An empty slice means no problems were found.
+func LintSchedule(props ScheduleJobPropertiesBuilder) []LintWarning {
+	_, body := props.buildSkeleton()
+	warnings := []LintWarning{}
+
+	if body.RunEvery != nil && body.RunEvery.isZero() {
+		warnings = append(warnings, LintWarning{
+			Code:    LintZeroRecurringDelta,
+			Message: "the job is recurring but the delta between runs is zero",
+		})
+	}
+
+	start, hasStart := lintStart(props, body)
+	if _, anchored := body.StartFrom.(startFromDatetime); anchored && body.RunEvery != nil &&
+		(body.RunEvery.Months != 0 || body.RunEvery.Years != 0) {
+		// The day the job recurs on is the day in its timezone, not in UTC.
+		if body.Timezone != "" {
+			if loc, err := time.LoadLocation(body.Timezone); err == nil {
+				start = start.In(loc)
+			}
+		}
+		if start.Day() > 28 {
+			warnings = append(warnings, LintWarning{
+				Code: LintMonthEndAnchor,
+				Message: fmt.Sprintf(
+					"the job recurs by months or years but is anchored on day %d, which "+
+						"does not exist in every month", start.Day(),
+				),
+			})
+		}
+	}
+
+	if hasStart && body.Until != "" {
+		until, err := time.Parse(time.RFC3339, body.Until)
+		if err == nil && until.Before(start) {
+			warnings = append(warnings, LintWarning{
+				Code:    LintEndsBeforeStart,
+				Message: "the job is set to stop running before it first runs",
+			})
+		}
+	}
+
+	return warnings
+}
+
+// Gets when the job first runs, if it is known. Jobs scheduled from now without an anchor
+// first run after their delay from the current time.
+func lintStart(props ScheduleJobPropertiesBuilder, body createJobSkeleton) (time.Time, bool) {
+	if start, ok := body.StartFrom.(startFromDatetime); ok {
+		t, err := time.Parse(time.RFC3339, start.DateTime)
+		return t, err == nil
+	}
+	if p, ok := props.(FromNowPropertiesBuilder); ok && p.anchor.IsZero() {
+		return p.d.addTo(time.Now()), true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,63 @@
+package sdk_test
+
+import (
+	"testing"
+	"time"
+
+	"go.clocktick.dev/sdk"
+)
+
+// Gets the codes of the warnings for the schedule.
+func lintCodes(props sdk.ScheduleJobPropertiesBuilder) []sdk.LintCode {
+	var codes []sdk.LintCode
+	for _, w := range sdk.LintSchedule(props) {
+		codes = append(codes, w.Code)
+	}
+	return codes
+}
+
+func TestLintMonthEndAnchorUsesTimezone(t *testing.T) {
+	tests := []struct {
+		name  string
+		props sdk.ScheduleJobPropertiesBuilder
+		warn  bool
+	}{
+		{
+			// This is 1 February in UTC but 31 January in New York.
+			name: "end of month in timezone",
+			props: sdk.FromTime(time.Date(2030, 2, 1, 3, 0, 0, 0, time.UTC)).
+				EveryMonths(1).Timezone("America/New_York"),
+			warn: true,
+		},
+		{
+			// This is 31 January in UTC but 1 February in Tokyo.
+			name: "start of month in timezone",
+			props: sdk.FromTime(time.Date(2030, 1, 31, 23, 0, 0, 0, time.UTC)).
+				EveryMonths(1).Timezone("Asia/Tokyo"),
+		},
+		{
+			name:  "end of month in UTC",
+			props: sdk.FromTime(time.Date(2030, 1, 31, 9, 0, 0, 0, time.UTC)).EveryMonths(1),
+			warn:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			codes := lintCodes(tt.props)
+			if got := len(codes) == 1 && codes[0] == sdk.LintMonthEndAnchor; got != tt.warn {
+				t.Errorf("warnings = %v, want month end warning %v", codes, tt.warn)
+			}
+		})
+	}
+}
+
+func TestLintEndsBeforeStartFromNow(t *testing.T) {
+	props := sdk.FromNow().Hours(2).Recurring().Until(time.Now().Add(time.Hour))
+	if codes := lintCodes(props); len(codes) != 1 || codes[0] != sdk.LintEndsBeforeStart {
+		t.Errorf("warnings = %v, want [%s]", codes, sdk.LintEndsBeforeStart)
+	}
+	props = sdk.FromNow().Hours(2).Recurring().Until(time.Now().Add(3 * time.Hour))
+	if codes := lintCodes(props); len(codes) != 0 {
+		t.Errorf("warnings = %v, want none", codes)
+	}
+}
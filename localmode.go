@@ -0,0 +1,335 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Defines the jobs scheduled in local mode.
+type localScheduler struct {
+	mu     sync.Mutex
+	nextID int
+	timers map[string]*time.Timer
+	jobs   map[string]*Job
+}
+
+// WithLocalMode is used to run jobs in-process with timers rather than through the API. Jobs
+// scheduled with ScheduleJob or ScheduleJobs are run by the server's own handlers when they
+// are due, without being encrypted or sent anywhere, so the full flow can be run offline.
+// GetJob, DeleteJob, and DeleteJobsWhere work on these jobs rather than the API. Jobs only
+// exist for the lifetime of the process. This is intended for development only.
+func WithLocalMode() ServerOption {
+	return func(s *Server) {
+		s.local = &localScheduler{timers: map[string]*time.Timer{}, jobs: map[string]*Job{}}
+	}
+}
+
+// Gets when a job with the properties specified first runs, and a function to get the run
+// after a run. next is nil for jobs which only run once. The end condition is applied after
+// runs are deferred out of exclusion windows, so it counts the runs which actually happen.
+func runTimes(
+	props ScheduleJobPropertiesBuilder, now time.Time,
+) (first time.Time, next func(prev time.Time) (time.Time, bool), err error) {
+	switch p := props.(type) {
+	case FromNowPropertiesBuilder:
+		base := now
+		if !p.anchor.IsZero() {
+			base = p.anchor
+		}
+		if p.recurring {
+			next = func(prev time.Time) (time.Time, bool) { return p.d.addTo(prev), true }
+		}
+		first = p.d.addTo(base).Add(jitterOffset(p.maxJitter))
+		first, next = p.exclusions.wrap(time.UTC, first, next)
+		return first, p.end.limit(next), nil
+	case FromTimePropertiesBuilder:
+		t := p.t.Add(jitterOffset(p.maxJitter))
+		loc := time.UTC
+		if p.timezone != "" {
+			loc, err = time.LoadLocation(p.timezone)
+			if err != nil {
+				return time.Time{}, nil, err
+			}
+			t = t.In(loc)
+		}
+		if p.d != nil {
+			d := *p.d
+			next = func(prev time.Time) (time.Time, bool) { return d.addTo(prev), true }
+		}
+		first, next = p.exclusions.wrap(loc, t, next)
+		return first, p.end.limit(next), nil
+	case FromCronPropertiesBuilder:
+		c, err := parseCron(p.expr)
+		if err != nil {
+			return time.Time{}, nil, err
+		}
+		first, ok := c.next(now.UTC())
+		if !ok {
+			return time.Time{}, nil, errors.New("cron expression never matches")
+		}
+		first, next = p.exclusions.wrap(time.UTC, first, c.next)
+		return first, p.end.limit(next), nil
+	case FromRRulePropertiesBuilder:
+		r, err := parseRRule(p.rule)
+		if err != nil {
+			return time.Time{}, nil, err
+		}
+		loc := time.UTC
+		if p.timezone != "" {
+			if loc, err = time.LoadLocation(p.timezone); err != nil {
+				return time.Time{}, nil, err
+			}
+		}
+		start := p.start
+		if start.IsZero() {
+			start = now.Truncate(time.Second)
+		}
+		start = start.In(loc)
+
+		// Include the start itself if it has not passed.
+		after := now
+		if !start.Before(now) {
+			after = start.Add(-time.Nanosecond)
+		}
+		first, ok := r.next(start, after)
+		if !ok {
+			return time.Time{}, nil, errors.New("RRULE never matches")
+		}
+		next = func(prev time.Time) (time.Time, bool) { return r.next(start, prev) }
+		first, next = p.exclusions.wrap(loc, first, next)
+		return first, next, nil
+	}
+	return time.Time{}, nil, errors.New("schedule is not supported in local mode")
+}
+
+// Schedules the job to run in-process. next holds the workflow steps to schedule once the
+// job succeeds, if any.
+func (s *Server) scheduleLocal(
+	ctx context.Context, route string, props ScheduleJobPropertiesBuilder, args []any,
+	next []JobSpec,
+) (JobCreationResponse, error) {
+	// Check the job in the same way as if it was being sent to the API.
+	r, ok := s.funcMap[route]
+	if !ok {
+		return JobCreationResponse{}, fmt.Errorf("%w: %s", ErrRouteNotFound, route)
+	}
+	props = s.anchorProps(props)
+	if err := validateProps(props, s.now(), s.strict); err != nil {
+		return JobCreationResponse{}, err
+	}
+	if r.numArgs != len(args) {
+		return JobCreationResponse{}, argumentMismatch(r.numArgs, len(args))
+	}
+	id, body := props.buildSkeleton()
+	if err := s.checkRecurringInterval(props, body.RunEvery); err != nil {
+		return JobCreationResponse{}, err
+	}
+	raws, err := encodeArgs(args)
+	if err != nil {
+		return JobCreationResponse{}, err
+	}
+	if err = r.checkArgs(raws); err != nil {
+		return JobCreationResponse{}, err
+	}
+	now := s.now()
+	first, nextRun, err := runTimes(props, now)
+	if err != nil {
+		return JobCreationResponse{}, err
+	}
+	baggage := s.collectBaggage(ctx)
+
+	// Start the timer for the first run.
+	l := s.local
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	customID := id
+	if id == "" {
+		l.nextID++
+		id = "local-" + strconv.Itoa(l.nextID)
+	}
+	if t, ok := l.timers[id]; ok {
+		// Replace the job with the same custom ID unless told to fail.
+		if body.OnConflict == string(conflictFail) {
+			return JobCreationResponse{}, ErrJobExists
+		}
+		t.Stop()
+	}
+	job := &Job{
+		ID: id, CustomID: customID, Route: route, EndpointID: s.defaultEndpointId,
+		Status: JobStatusScheduled, RunEvery: body.RunEvery, CreatedAt: now.UTC(),
+		Tags: body.Tags, Metadata: body.Metadata,
+	}
+	l.jobs[id] = job
+	var schedule func(at time.Time)
+	schedule = func(at time.Time) {
+		job.NextRunAt = &at
+		var t *time.Timer
+		t = time.AfterFunc(at.Sub(s.now()), func() {
+			jc := JobContext{
+				JobID: id, CustomID: customID, Route: route, ScheduledAt: at, Attempt: 1,
+				EndpointID: s.defaultEndpointId,
+			}
+			if s.runLocal(jc, raws, baggage) && len(next) != 0 {
+				ctx := s.restoreBaggage(context.Background(), baggage)
+				if _, err := s.ScheduleWorkflow(ctx, next...); err != nil {
+					s.logger.Error(
+						"failed to schedule next workflow step", "route", route, "job_id", id,
+						"error", err,
+					)
+				}
+			}
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			if l.timers[id] != t {
+				// The job was replaced whilst it was running.
+				return
+			}
+			if nextRun != nil {
+				// Stop if the schedule has ended or would not move forwards.
+				if n, ok := nextRun(at); ok && n.After(at) {
+					schedule(n)
+					return
+				}
+			}
+			delete(l.timers, id)
+			job.Status = JobStatusCompleted
+			job.NextRunAt = nil
+		})
+		l.timers[id] = t
+	}
+	schedule(first)
+	return JobCreationResponse{JobID: id}, nil
+}
+
+// GetJob is used to get the job with the ID specified. In local mode, the job is looked up
+// in the jobs the server has scheduled, returning ErrJobNotFound if there is none.
+func (s *Server) GetJob(ctx context.Context, jobId string) (Job, error) {
+	if s.local == nil {
+		return s.Client.GetJob(ctx, jobId)
+	}
+	if jobId == "" {
+		return Job{}, errors.New("job ID is required")
+	}
+	l := s.local
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	job, ok := l.jobs[jobId]
+	if !ok {
+		return Job{}, ErrJobNotFound
+	}
+	return *job, nil
+}
+
+// DeleteJob is used to delete the job with the ID specified. In local mode, the timer of the
+// job is stopped, returning ErrJobNotFound if there is no such job.
+func (s *Server) DeleteJob(ctx context.Context, jobId string) error {
+	if s.local == nil {
+		return s.Client.DeleteJob(ctx, jobId)
+	}
+	if jobId == "" {
+		return errors.New("job ID is required")
+	}
+	l := s.local
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.jobs[jobId]; !ok {
+		return ErrJobNotFound
+	}
+	if t, ok := l.timers[jobId]; ok {
+		t.Stop()
+		delete(l.timers, jobId)
+	}
+	delete(l.jobs, jobId)
+	return nil
+}
+
+// GetJobByCustomID is used to get the job with the custom ID specified. See
+// Client.GetJobByCustomID. In local mode, the job is looked up in the jobs the server has
+// scheduled.
+func (s *Server) GetJobByCustomID(ctx context.Context, customId string) (Job, error) {
+	if s.local == nil || customId == "" {
+		return s.Client.GetJobByCustomID(ctx, customId)
+	}
+	// Jobs with a custom ID are stored under it in local mode.
+	job, err := s.GetJob(ctx, customId)
+	if err == nil && job.CustomID != customId {
+		return Job{}, ErrJobNotFound
+	}
+	return job, err
+}
+
+// DeleteJobByCustomID is used to delete the job with the custom ID specified. See
+// Client.DeleteJobByCustomID. In local mode, the job is deleted from the jobs the server has
+// scheduled.
+func (s *Server) DeleteJobByCustomID(ctx context.Context, customId string) error {
+	job, err := s.GetJobByCustomID(ctx, customId)
+	if err != nil {
+		return err
+	}
+	return s.DeleteJob(ctx, job.ID)
+}
+
+// DeleteJobsWhere is used to delete every job matching the filter. See
+// Client.DeleteJobsWhere. In local mode, the matching jobs the server has scheduled are
+// deleted and their timers stopped.
+func (s *Server) DeleteJobsWhere(ctx context.Context, filter DeleteJobsFilter) (int, error) {
+	if s.local == nil {
+		return s.Client.DeleteJobsWhere(ctx, filter)
+	}
+	if filter == (DeleteJobsFilter{}) {
+		return 0, errEmptyDeleteFilter
+	}
+	l := s.local
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	deleted := 0
+	for id, job := range l.jobs {
+		if !filter.matches(*job) {
+			continue
+		}
+		if t, ok := l.timers[id]; ok {
+			t.Stop()
+			delete(l.timers, id)
+		}
+		delete(l.jobs, id)
+		deleted++
+	}
+	return deleted, nil
+}
+
+// Runs a job scheduled in local mode through the middleware chain, returning true if it
+// succeeded.
+func (s *Server) runLocal(
+	jc JobContext, raws []msgpack.RawMessage, baggage map[string]string,
+) (succeeded bool) {
+	route, id := jc.Route, jc.JobID
+	r, ok := s.funcMap[route]
+	if !ok {
+		s.logger.Warn("route not found", "route", route, "job_id", id)
+		return
+	}
+	ctx := s.restoreBaggage(context.Background(), baggage)
+	ctx = context.WithValue(ctx, jobContextKey{}, jc)
+	inv := &Invocation{Route: route, JobID: id, Args: raws}
+	var err error
+	if p, stack := panicCondomStack(func() { err = s.chain(r)(ctx, inv) }); p != nil {
+		s.incr(counterPanics)
+		s.logger.Error("handler panicked", "route", route, "job_id", id, "panic", p)
+		s.handlePanic(ctx, route, p, stack)
+		s.reportPanic(ctx, route, p, stack)
+		return
+	}
+	if err != nil {
+		s.logger.Error("handler returned an error", "route", route, "job_id", id, "error", err)
+		s.handleError(ctx, route, err)
+		s.reportError(ctx, route, err)
+		return
+	}
+	return true
+}
@@ -0,0 +1,156 @@
+package sdk_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"go.clocktick.dev/sdk"
+)
+
+// Waits for the function to return true, failing the test if it does not within a second.
+func eventually(t *testing.T, f func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !f() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met within a second")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestLocalModeUsesServerClock(t *testing.T) {
+	api := newRecordingAPI(t)
+
+	// By the real clock the job is years overdue, but by the server clock it is an hour away.
+	now := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	s, _ := newTestServer(
+		t, api.URL, sdk.WithLocalMode(), sdk.WithClock(func() time.Time { return now }),
+	)
+	r, calls := addRecordingRoute(s, "email", nil)
+
+	res, err := r.Schedule(context.Background(), sdk.FromTime(now.Add(time.Hour)), "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	job, err := s.GetJob(context.Background(), res.JobID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.NextRunAt == nil || !job.NextRunAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("NextRunAt = %v, want %v", job.NextRunAt, now.Add(time.Hour))
+	}
+	if !job.CreatedAt.Equal(now) {
+		t.Errorf("CreatedAt = %v, want %v", job.CreatedAt, now)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if n := len(calls()); n != 0 {
+		t.Errorf("job ran %d times before it was due", n)
+	}
+	if n := len(api.received()); n != 0 {
+		t.Errorf("API received %d requests, want 0", n)
+	}
+	if err := s.DeleteJob(context.Background(), res.JobID); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLocalModeGetAndDeleteJob(t *testing.T) {
+	api := newRecordingAPI(t)
+	s, _ := newTestServer(t, api.URL, sdk.WithLocalMode())
+	r, calls := addRecordingRoute(s, "email", nil)
+	ctx := context.Background()
+
+	props := sdk.FromNow().Milliseconds(100).CustomID("welcome").Tag("onboarding")
+	res, err := r.Schedule(ctx, props, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	job, err := s.GetJob(ctx, res.JobID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Status != sdk.JobStatusScheduled || job.Route != "email" || job.NextRunAt == nil {
+		t.Errorf("got job %+v", job)
+	}
+	if len(job.Tags) != 1 || job.Tags[0] != "onboarding" {
+		t.Errorf("tags = %v, want [onboarding]", job.Tags)
+	}
+	if byCustom, err := s.GetJobByCustomID(ctx, "welcome"); err != nil || byCustom.ID != job.ID {
+		t.Errorf("GetJobByCustomID = %+v, %v", byCustom, err)
+	}
+
+	if err := s.DeleteJob(ctx, res.JobID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.GetJob(ctx, res.JobID); !errors.Is(err, sdk.ErrJobNotFound) {
+		t.Errorf("GetJob after delete = %v, want ErrJobNotFound", err)
+	}
+	if err := s.DeleteJob(ctx, res.JobID); !errors.Is(err, sdk.ErrJobNotFound) {
+		t.Errorf("second DeleteJob = %v, want ErrJobNotFound", err)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	if n := len(calls()); n != 0 {
+		t.Errorf("deleted job ran %d times", n)
+	}
+	if n := len(api.received()); n != 0 {
+		t.Errorf("API received %d requests, want 0", n)
+	}
+}
+
+func TestLocalModeRecurringJobStopsAtMaxRuns(t *testing.T) {
+	s, _ := newTestServer(t, "http://127.0.0.1:0", sdk.WithLocalMode())
+	r, calls := addRecordingRoute(s, "tick", nil)
+
+	props := sdk.FromNow().Milliseconds(10).Recurring().MaxRuns(3)
+	res, err := r.Schedule(context.Background(), props, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, func() bool {
+		job, err := s.GetJob(context.Background(), res.JobID)
+		return err == nil && job.Status == sdk.JobStatusCompleted
+	})
+	if n := len(calls()); n != 3 {
+		t.Errorf("job ran %d times, want 3", n)
+	}
+}
+
+func TestLocalModeDeleteJobsWhere(t *testing.T) {
+	api := newRecordingAPI(t)
+	s, _ := newTestServer(t, api.URL, sdk.WithLocalMode())
+	r, calls := addRecordingRoute(s, "email", nil)
+	ctx := context.Background()
+
+	var kept string
+	for i, tag := range []string{"customer_1", "customer_1", "customer_2"} {
+		res, err := r.Schedule(ctx, sdk.FromNow().Milliseconds(100).Tag(tag), strconv.Itoa(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		kept = res.JobID
+	}
+
+	if _, err := s.DeleteJobsWhere(ctx, sdk.DeleteJobsFilter{}); err == nil {
+		t.Error("expected an error for an empty filter")
+	}
+	n, err := s.DeleteJobsWhere(ctx, sdk.DeleteJobsFilter{Route: "email", Tag: "customer_1"})
+	if err != nil || n != 2 {
+		t.Fatalf("DeleteJobsWhere = %d, %v, want 2 deleted", n, err)
+	}
+	if _, err := s.GetJob(ctx, kept); err != nil {
+		t.Errorf("job of the other customer: %v", err)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	if got := calls(); len(got) != 1 || got[0] != "2" {
+		t.Errorf("handler got %v, want [2]", got)
+	}
+	if n := len(api.received()); n != 0 {
+		t.Errorf("API received %d requests, want 0", n)
+	}
+}
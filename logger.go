@@ -0,0 +1,28 @@
+package sdk
+
+// Logger is used to define the structured logger the server logs to. keysAndValues are
+// alternating keys and values. *slog.Logger satisfies this interface, and adapters for other
+// logging libraries are in the logging directory.
+type Logger interface {
+	Debug(msg string, keysAndValues ...any)
+	Info(msg string, keysAndValues ...any)
+	Warn(msg string, keysAndValues ...any)
+	Error(msg string, keysAndValues ...any)
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...any) {}
+func (nopLogger) Info(string, ...any)  {}
+func (nopLogger) Warn(string, ...any)  {}
+func (nopLogger) Error(string, ...any) {}
+
+// WithLogger is used to set the logger the server logs to. By default, nothing is logged.
+func WithLogger(l Logger) ServerOption {
+	return func(s *Server) {
+		if l == nil {
+			l = nopLogger{}
+		}
+		s.logger = l
+	}
+}
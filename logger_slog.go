@@ -0,0 +1,8 @@
+//go:build go1.21
+
+package sdk
+
+import "log/slog"
+
+// Make sure a *slog.Logger can be passed to WithLogger as is.
+var _ Logger = (*slog.Logger)(nil)
@@ -0,0 +1,41 @@
+// Package logrusadapter is used to send the logs from the Clocktick SDK to a logrus logger.
+package logrusadapter
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"go.clocktick.dev/sdk"
+)
+
+type logger struct {
+	l logrus.FieldLogger
+}
+
+// Turns the alternating keys and values into logrus fields.
+func (l logger) entry(keysAndValues []any) logrus.FieldLogger {
+	if len(keysAndValues) == 0 {
+		return l.l
+	}
+	fields := make(logrus.Fields, len(keysAndValues)/2)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		key := fmt.Sprint(keysAndValues[i])
+		if i+1 == len(keysAndValues) {
+			// Match slog and zap by logging a dangling value under a placeholder key.
+			fields["!BADKEY"] = keysAndValues[i]
+			break
+		}
+		fields[key] = keysAndValues[i+1]
+	}
+	return l.l.WithFields(fields)
+}
+
+func (l logger) Debug(msg string, keysAndValues ...any) { l.entry(keysAndValues).Debug(msg) }
+func (l logger) Info(msg string, keysAndValues ...any)  { l.entry(keysAndValues).Info(msg) }
+func (l logger) Warn(msg string, keysAndValues ...any)  { l.entry(keysAndValues).Warn(msg) }
+func (l logger) Error(msg string, keysAndValues ...any) { l.entry(keysAndValues).Error(msg) }
+
+// New is used to create a sdk.Logger which logs to the logrus logger or entry specified.
+func New(l logrus.FieldLogger) sdk.Logger {
+	return logger{l: l}
+}
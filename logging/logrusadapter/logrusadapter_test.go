@@ -0,0 +1,35 @@
+package logrusadapter_test
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"go.clocktick.dev/sdk/logging/logrusadapter"
+)
+
+func TestLogsWithFields(t *testing.T) {
+	l, hook := test.NewNullLogger()
+	l.SetLevel(logrus.DebugLevel)
+	logrusadapter.New(l).Warn("delivery failed", "route", "email", "attempt", 2)
+
+	e := hook.LastEntry()
+	if e == nil {
+		t.Fatal("nothing was logged")
+	}
+	if e.Level != logrus.WarnLevel || e.Message != "delivery failed" {
+		t.Errorf("got %s %q, want warning \"delivery failed\"", e.Level, e.Message)
+	}
+	if e.Data["route"] != "email" || e.Data["attempt"] != 2 {
+		t.Errorf("fields = %v", e.Data)
+	}
+}
+
+func TestLogsDanglingValue(t *testing.T) {
+	l, hook := test.NewNullLogger()
+	logrusadapter.New(l).Info("scheduled", "route", "email", "extra")
+
+	if got := hook.LastEntry().Data["!BADKEY"]; got != "extra" {
+		t.Errorf("!BADKEY = %v, want extra", got)
+	}
+}
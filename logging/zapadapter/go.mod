@@ -0,0 +1,18 @@
+module go.clocktick.dev/sdk/logging/zapadapter
+
+go 1.20
+
+require (
+	go.clocktick.dev/sdk v0.0.0
+	go.uber.org/zap v1.27.0
+)
+
+require (
+	github.com/vmihailenco/msgpack/v5 v5.4.1 // indirect
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+	go.uber.org/multierr v1.10.0 // indirect
+	golang.org/x/crypto v0.21.0 // indirect
+	golang.org/x/sys v0.18.0 // indirect
+)
+
+replace go.clocktick.dev/sdk => ../..
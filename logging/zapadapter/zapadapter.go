@@ -0,0 +1,26 @@
+// Package zapadapter is used to send the logs from the Clocktick SDK to a zap logger.
+package zapadapter
+
+import (
+	"go.clocktick.dev/sdk"
+	"go.uber.org/zap"
+)
+
+type logger struct {
+	l *zap.SugaredLogger
+}
+
+func (l logger) Debug(msg string, keysAndValues ...any) { l.l.Debugw(msg, keysAndValues...) }
+func (l logger) Info(msg string, keysAndValues ...any)  { l.l.Infow(msg, keysAndValues...) }
+func (l logger) Warn(msg string, keysAndValues ...any)  { l.l.Warnw(msg, keysAndValues...) }
+func (l logger) Error(msg string, keysAndValues ...any) { l.l.Errorw(msg, keysAndValues...) }
+
+// New is used to create a sdk.Logger which logs to the zap logger specified.
+func New(l *zap.Logger) sdk.Logger {
+	return logger{l: l.Sugar()}
+}
+
+// NewSugared is used to create a sdk.Logger which logs to the sugared zap logger specified.
+func NewSugared(l *zap.SugaredLogger) sdk.Logger {
+	return logger{l: l}
+}
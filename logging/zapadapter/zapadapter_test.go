@@ -0,0 +1,34 @@
+package zapadapter_test
+
+import (
+	"testing"
+
+	"go.clocktick.dev/sdk/logging/zapadapter"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLogsAtEachLevel(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	l := zapadapter.New(zap.New(core))
+
+	l.Debug("debug", "route", "email")
+	l.Info("info", "route", "email")
+	l.Warn("warn", "route", "email")
+	l.Error("error", "route", "email")
+
+	want := []zapcore.Level{zapcore.DebugLevel, zapcore.InfoLevel, zapcore.WarnLevel, zapcore.ErrorLevel}
+	entries := logs.AllUntimed()
+	if len(entries) != len(want) {
+		t.Fatalf("got %d entries, want %d", len(entries), len(want))
+	}
+	for i, e := range entries {
+		if e.Level != want[i] {
+			t.Errorf("entry %d level = %s, want %s", i, e.Level, want[i])
+		}
+		if got := e.ContextMap()["route"]; got != "email" {
+			t.Errorf("entry %d route = %v, want email", i, got)
+		}
+	}
+}
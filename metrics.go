@@ -0,0 +1,38 @@
+package sdk
+
+import "time"
+
+// Metrics is used to record metrics about the deliveries a server handles. See the
+// metrics/promadapter module for a Prometheus implementation.
+type Metrics interface {
+	// DeliveryReceived is called when a delivery is received, before it is verified.
+	DeliveryReceived()
+
+	// SignatureFailed is called when a delivery fails signature or timestamp verification.
+	SignatureFailed()
+
+	// DecryptFailed is called when the payload of a delivery for the route cannot be decrypted.
+	DecryptFailed(route string)
+
+	// JobHandled is called once the handler of the route returns or panics, with whether it
+	// succeeded and how long it took.
+	JobHandled(route string, success bool, duration time.Duration)
+}
+
+type nopMetrics struct{}
+
+func (nopMetrics) DeliveryReceived()                      {}
+func (nopMetrics) SignatureFailed()                       {}
+func (nopMetrics) DecryptFailed(string)                   {}
+func (nopMetrics) JobHandled(string, bool, time.Duration) {}
+
+// WithMetrics is used to set where the server records its delivery metrics. By default, they
+// are not recorded.
+func WithMetrics(m Metrics) ServerOption {
+	return func(s *Server) {
+		if m == nil {
+			m = nopMetrics{}
+		}
+		s.metrics = m
+	}
+}
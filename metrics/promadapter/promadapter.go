@@ -0,0 +1,95 @@
+// Package promadapter is used to export the delivery metrics of the Clocktick SDK to
+// Prometheus.
+package promadapter
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.clocktick.dev/sdk"
+)
+
+// Metrics is used to record the delivery metrics of a server as Prometheus metrics. It is
+// both a sdk.Metrics, to be passed to sdk.WithMetrics, and a prometheus.Collector, to be
+// registered with a registry.
+type Metrics struct {
+	deliveries        prometheus.Counter
+	signatureFailures prometheus.Counter
+	decryptFailures   *prometheus.CounterVec
+	jobs              *prometheus.CounterVec
+	jobDuration       *prometheus.HistogramVec
+}
+
+var _ sdk.Metrics = (*Metrics)(nil)
+
+var _ prometheus.Collector = (*Metrics)(nil)
+
+// New is used to create the metrics. The metric names are prefixed with clocktick_.
+func New() *Metrics {
+	return &Metrics{
+		deliveries: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "clocktick_deliveries_total",
+			Help: "The number of deliveries received.",
+		}),
+		signatureFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "clocktick_signature_failures_total",
+			Help: "The number of deliveries which failed signature or timestamp verification.",
+		}),
+		decryptFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "clocktick_decrypt_failures_total",
+			Help: "The number of deliveries whose payload could not be decrypted.",
+		}, []string{"route"}),
+		jobs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "clocktick_jobs_total",
+			Help: "The number of jobs handled, by route and result.",
+		}, []string{"route", "result"}),
+		jobDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "clocktick_job_duration_seconds",
+			Help:    "How long the handlers of jobs took to run.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"route"}),
+	}
+}
+
+// DeliveryReceived implements sdk.Metrics.
+func (m *Metrics) DeliveryReceived() {
+	m.deliveries.Inc()
+}
+
+// SignatureFailed implements sdk.Metrics.
+func (m *Metrics) SignatureFailed() {
+	m.signatureFailures.Inc()
+}
+
+// DecryptFailed implements sdk.Metrics.
+func (m *Metrics) DecryptFailed(route string) {
+	m.decryptFailures.WithLabelValues(route).Inc()
+}
+
+// JobHandled implements sdk.Metrics.
+func (m *Metrics) JobHandled(route string, success bool, duration time.Duration) {
+	result := "success"
+	if !success {
+		result = "failure"
+	}
+	m.jobs.WithLabelValues(route, result).Inc()
+	m.jobDuration.WithLabelValues(route).Observe(duration.Seconds())
+}
+
+// Describe implements prometheus.Collector.
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.deliveries.Describe(ch)
+	m.signatureFailures.Describe(ch)
+	m.decryptFailures.Describe(ch)
+	m.jobs.Describe(ch)
+	m.jobDuration.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.deliveries.Collect(ch)
+	m.signatureFailures.Collect(ch)
+	m.decryptFailures.Collect(ch)
+	m.jobs.Collect(ch)
+	m.jobDuration.Collect(ch)
+}
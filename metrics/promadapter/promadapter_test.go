@@ -0,0 +1,50 @@
+package promadapter_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.clocktick.dev/sdk/metrics/promadapter"
+)
+
+func TestRecordsMetrics(t *testing.T) {
+	m := promadapter.New()
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(m)
+
+	m.DeliveryReceived()
+	m.DeliveryReceived()
+	m.SignatureFailed()
+	m.DecryptFailed("email")
+	m.JobHandled("email", true, time.Second)
+	m.JobHandled("email", false, time.Second)
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]float64{}
+	for _, f := range families {
+		for _, metric := range f.GetMetric() {
+			switch {
+			case metric.GetCounter() != nil:
+				got[f.GetName()] += metric.GetCounter().GetValue()
+			case metric.GetHistogram() != nil:
+				got[f.GetName()] += float64(metric.GetHistogram().GetSampleCount())
+			}
+		}
+	}
+	want := map[string]float64{
+		"clocktick_deliveries_total":         2,
+		"clocktick_signature_failures_total": 1,
+		"clocktick_decrypt_failures_total":   1,
+		"clocktick_jobs_total":               2,
+		"clocktick_job_duration_seconds":     2,
+	}
+	for name, v := range want {
+		if got[name] != v {
+			t.Errorf("%s = %v, want %v", name, got[name], v)
+		}
+	}
+}
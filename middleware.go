@@ -0,0 +1,49 @@
+package sdk
+
+import (
+	"context"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Invocation is used to describe a single job execution passed through the middleware chain.
+type Invocation struct {
+	// Route is the route the job was scheduled against.
+	Route string
+
+	// JobID is the ID of the job being delivered.
+	JobID string
+
+	// Args are the raw arguments of the job after they have been fitted to the route.
+	Args []msgpack.RawMessage
+
+	// Result is the value returned by the handler, set once the innermost HandlerFunc returns.
+	// Middleware may replace it before it is sent back to the platform.
+	Result any
+}
+
+// HandlerFunc is used to execute a job. The innermost HandlerFunc calls the route itself.
+type HandlerFunc func(ctx context.Context, inv *Invocation) error
+
+// Middleware is used to wrap every job execution on a server.
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// Use is used to add middleware that wraps every job execution. Middleware added first is the
+// outermost, so it sees the job before and the result after any middleware added later. Use
+// should be called before the server starts handling deliveries.
+func (s *Server) Use(mw ...Middleware) {
+	s.middleware = append(s.middleware, mw...)
+}
+
+// Wraps the route with the middleware chain.
+func (s *Server) chain(route funcOpts) HandlerFunc {
+	h := HandlerFunc(func(ctx context.Context, inv *Invocation) error {
+		result, err := route.call(ctx, inv.Args)
+		inv.Result = result
+		return err
+	})
+	for i := len(s.middleware) - 1; i >= 0; i-- {
+		h = s.middleware[i](h)
+	}
+	return h
+}
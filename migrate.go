@@ -0,0 +1,59 @@
+package sdk
+
+import (
+	"fmt"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Migrator is used to convert the raw msgpack arguments of a job scheduled against an older
+// version of a route into the arguments of the version the migrator is added to. version is
+// the version the job was scheduled against, which is 0 for jobs scheduled without one.
+type Migrator func(version uint, args []msgpack.RawMessage) ([]msgpack.RawMessage, error)
+
+// Migrate is used to set the migrator of the route as an option. When a job is delivered for
+// a version of the route which has not been added, the arguments are passed through the
+// migrator of the highest version and that version is run, so old versions do not need to be
+// kept around once a migrator can convert their arguments. An error from the migrator fails
+// the delivery in the same way as an argument which cannot be decoded.
+func Migrate(m Migrator) Option {
+	if m == nil {
+		panic("m must not be nil")
+	}
+	return Option{migrator: m}
+}
+
+// Gets the migrator of the route, or nil if it has none.
+func (f funcOpts) migrator() Migrator {
+	var m Migrator
+	for _, opt := range f.a {
+		if opt.migrator != nil {
+			m = opt.migrator
+		}
+	}
+	return m
+}
+
+// Picks the version of the route to run the payload with, migrating the arguments if the
+// version it was scheduled against has not been added.
+func (s *Server) resolveVersion(
+	route string, latest funcOpts, env payloadEnvelope,
+) (funcOpts, []msgpack.RawMessage, error) {
+	if env.Version == latest.version() {
+		return latest, env.Args, nil
+	}
+	if r, ok := s.routeVersion(route, env.Version); ok {
+		return r, env.Args, nil
+	}
+	m := latest.migrator()
+	if m == nil {
+		return latest, nil, fmt.Errorf("%w: %s version %d", ErrRouteNotFound, route, env.Version)
+	}
+	raws, err := m(env.Version, env.Args)
+	if err != nil {
+		return latest, nil, fmt.Errorf(
+			"failed to migrate arguments from version %d of route %s: %w", env.Version, route, err,
+		)
+	}
+	return latest, raws, nil
+}
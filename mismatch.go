@@ -0,0 +1,71 @@
+package sdk
+
+import "github.com/vmihailenco/msgpack/v5"
+
+// ArgumentMismatchPolicy defines what happens when a delivery has a different number of
+// arguments to the route's handler, which happens when a handler's signature changes whilst
+// jobs scheduled against the old signature are still pending.
+type ArgumentMismatchPolicy int
+
+const (
+	// ArgumentMismatchReject rejects the delivery with a 400. This is the default.
+	ArgumentMismatchReject ArgumentMismatchPolicy = iota
+
+	// ArgumentMismatchPad passes zero values for missing arguments but rejects extra ones.
+	ArgumentMismatchPad
+
+	// ArgumentMismatchTruncate drops extra arguments but rejects missing ones.
+	ArgumentMismatchTruncate
+
+	// ArgumentMismatchPadOrTruncate both pads missing arguments and drops extra ones.
+	ArgumentMismatchPadOrTruncate
+)
+
+// String is used to get the name of the policy for logging.
+func (p ArgumentMismatchPolicy) String() string {
+	switch p {
+	case ArgumentMismatchPad:
+		return "pad"
+	case ArgumentMismatchTruncate:
+		return "truncate"
+	case ArgumentMismatchPadOrTruncate:
+		return "pad_or_truncate"
+	default:
+		return "reject"
+	}
+}
+
+// ArgumentMismatch is used to set what the route does when a delivery has the wrong number of
+// arguments as an option.
+func ArgumentMismatch(policy ArgumentMismatchPolicy) Option {
+	return Option{argumentMismatch: &policy}
+}
+
+// Gets the argument mismatch policy of the route.
+func (f funcOpts) argumentMismatch() ArgumentMismatchPolicy {
+	policy := ArgumentMismatchReject
+	for _, opt := range f.a {
+		if opt.argumentMismatch != nil {
+			policy = *opt.argumentMismatch
+		}
+	}
+	return policy
+}
+
+// Fits the arguments to the route using its policy. Returns false if they cannot be fitted.
+func (f funcOpts) fitArgs(raws []msgpack.RawMessage) ([]msgpack.RawMessage, bool) {
+	policy := f.argumentMismatch()
+	switch {
+	case len(raws) == f.numArgs:
+		return raws, true
+	case len(raws) < f.numArgs && (policy == ArgumentMismatchPad || policy == ArgumentMismatchPadOrTruncate):
+		// A nil raw message decodes to the zero value.
+		padded := make([]msgpack.RawMessage, f.numArgs)
+		copy(padded, raws)
+		return padded, true
+	case len(raws) > f.numArgs && (policy == ArgumentMismatchTruncate || policy == ArgumentMismatchPadOrTruncate):
+		return raws[:f.numArgs], true
+	default:
+		return raws, false
+	}
+}
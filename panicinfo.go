@@ -0,0 +1,60 @@
+package sdk
+
+import (
+	"context"
+	"runtime/debug"
+)
+
+// PanicInfo defines the details of a handler that panicked.
+type PanicInfo struct {
+	// Route is the route of the job.
+	Route string
+
+	// Job is the job that was being run.
+	Job JobContext
+
+	// Value is the value the handler panicked with.
+	Value any
+
+	// Stack is the stack trace of the goroutine at the point it panicked.
+	Stack []byte
+}
+
+// SetPanicInfoHandler is used to set the function called with the details of the job and the
+// stack trace when a handler panics. When set, it is called instead of the panic handler.
+func (s *Server) SetPanicInfoHandler(f func(ctx context.Context, info PanicInfo)) {
+	s.panicInfoHandler = f
+}
+
+// WithPanicInfoHandler is used to set the function called with the details of the job and the
+// stack trace when a handler panics. When set, it is called instead of the panic handler.
+func WithPanicInfoHandler(f func(ctx context.Context, info PanicInfo)) ServerOption {
+	return func(s *Server) {
+		s.panicInfoHandler = f
+	}
+}
+
+// Calls the function, returning the value and the stack trace if it panicked.
+func panicCondomStack(f func()) (val any, stack []byte) {
+	defer func() {
+		if r := recover(); r != nil {
+			val = r
+			stack = debug.Stack()
+		}
+	}()
+	f()
+	return
+}
+
+// Passes a panic from the handler of a route on to the panic handlers.
+func (s *Server) handlePanic(ctx context.Context, route string, val any, stack []byte) {
+	if s.panicInfoHandler == nil {
+		s.panicHandler(val)
+		return
+	}
+	info := PanicInfo{Route: route, Value: val, Stack: stack}
+	info.Job, _ = JobFromContext(ctx)
+	if v := panicCondom(func() { s.panicInfoHandler(ctx, info) }); v != nil {
+		s.logger.Error("panic info handler panicked", "route", route, "panic", v)
+	}
+}
@@ -0,0 +1,80 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"github.com/vmihailenco/msgpack/v5/msgpcode"
+)
+
+// Defines the envelope the arguments are wrapped in when the job carries more than just
+// its arguments. Payloads with nothing but arguments are sent as a plain msgpack array so
+// that they stay readable by older SDKs.
+type payloadEnvelope struct {
+	Args    []msgpack.RawMessage `msgpack:"a"`
+	Baggage map[string]string    `msgpack:"b,omitempty"`
+	Trace   map[string]string    `msgpack:"t,omitempty"`
+
+	// When the arguments are compressed, they are in Compressed instead of Args and
+	// Compression is the name of the compressor.
+	Compression string `msgpack:"z,omitempty"`
+	Compressed  []byte `msgpack:"d,omitempty"`
+
+	// When the payload is in a PayloadStore, the envelope has nothing but its reference.
+	Ref string `msgpack:"r,omitempty"`
+
+	// Next holds the workflow steps to schedule once the job succeeds.
+	Next []workflowStep `msgpack:"n,omitempty"`
+
+	// Version is the version of the route the arguments were encoded for.
+	Version uint `msgpack:"v,omitempty"`
+}
+
+// Encodes the payload, only using the envelope when it is required.
+func encodePayload(env payloadEnvelope) ([]byte, error) {
+	if len(env.Baggage) == 0 && len(env.Trace) == 0 && env.Compression == "" && env.Ref == "" &&
+		len(env.Next) == 0 && env.Version == 0 {
+		return msgpack.Marshal(env.Args)
+	}
+	return msgpack.Marshal(env)
+}
+
+// Compresses the envelope and encodes it, putting it in the payload store if it is too large.
+func (c *Client) packPayload(ctx context.Context, env payloadEnvelope) ([]byte, error) {
+	env, err := c.compressPayload(env)
+	if err != nil {
+		return nil, err
+	}
+	b, err := encodePayload(env)
+	if err != nil {
+		return nil, err
+	}
+	return c.offloadPayload(ctx, b)
+}
+
+// Encodes the arguments into their raw msgpack form.
+func encodeArgs(args []any) ([]msgpack.RawMessage, error) {
+	raws := make([]msgpack.RawMessage, len(args))
+	for i, arg := range args {
+		b, err := msgpack.Marshal(arg)
+		if err != nil {
+			return nil, err
+		}
+		raws[i] = b
+	}
+	return raws, nil
+}
+
+// Decodes the payload, handling both plain arrays and envelopes.
+func decodePayload(b []byte) (env payloadEnvelope, err error) {
+	if len(b) == 0 {
+		return env, errors.New("empty payload")
+	}
+	if c := b[0]; msgpcode.IsFixedMap(c) || c == msgpcode.Map16 || c == msgpcode.Map32 {
+		err = msgpack.Unmarshal(b, &env)
+		return
+	}
+	err = msgpack.Unmarshal(b, &env.Args)
+	return
+}
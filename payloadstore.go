@@ -0,0 +1,118 @@
+package sdk
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// PayloadStore is used to keep the arguments of large jobs outside of the job API, so that
+// only a reference to them travels with the job. The data is encrypted before it is put in
+// the store, so the store never sees the arguments. Stored payloads are never deleted by the
+// SDK since recurring jobs read them on every run, so use the lifecycle rules of the store to
+// expire them once the jobs are finished with.
+type PayloadStore interface {
+	// Put is used to store the data, returning the reference it can be fetched with.
+	Put(ctx context.Context, data []byte) (ref string, err error)
+
+	// Get is used to fetch the data stored with the reference.
+	Get(ctx context.Context, ref string) ([]byte, error)
+}
+
+// WithPayloadStore is used to put the arguments of the jobs the server schedules in the store
+// when they are at least minSize bytes once encoded, and to fetch them when a job which was
+// stored is delivered. The server and everything scheduling jobs for it must use the same store.
+func WithPayloadStore(store PayloadStore, minSize int) ServerOption {
+	return func(s *Server) {
+		WithClientPayloadStore(store, minSize)(s.Client)
+	}
+}
+
+// WithClientPayloadStore is used to put the arguments of the jobs the client schedules in the
+// store when they are at least minSize bytes once encoded.
+func WithClientPayloadStore(store PayloadStore, minSize int) ClientOption {
+	return func(c *Client) {
+		c.payloadStore = store
+		c.offloadMinSize = minSize
+	}
+}
+
+// MemoryPayloadStore is an in-memory PayloadStore. It is only useful for tests and local mode
+// since the payloads are lost when the process exits and are not shared between processes.
+type MemoryPayloadStore struct {
+	mu       sync.Mutex
+	payloads map[string][]byte
+}
+
+var _ PayloadStore = (*MemoryPayloadStore)(nil)
+
+// NewMemoryPayloadStore is used to create an empty in-memory payload store.
+func NewMemoryPayloadStore() *MemoryPayloadStore {
+	return &MemoryPayloadStore{payloads: map[string][]byte{}}
+}
+
+// Put implements PayloadStore.
+func (m *MemoryPayloadStore) Put(_ context.Context, data []byte) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	ref := hex.EncodeToString(b)
+	m.mu.Lock()
+	m.payloads[ref] = append([]byte(nil), data...)
+	m.mu.Unlock()
+	return ref, nil
+}
+
+// Get implements PayloadStore.
+func (m *MemoryPayloadStore) Get(_ context.Context, ref string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.payloads[ref]
+	if !ok {
+		return nil, errors.New("payload not found")
+	}
+	return data, nil
+}
+
+// Puts the encoded payload in the store if one is set and it is large enough, returning the
+// payload to send with the job instead.
+func (c *Client) offloadPayload(ctx context.Context, b []byte) ([]byte, error) {
+	if c.payloadStore == nil || len(b) < c.offloadMinSize {
+		return b, nil
+	}
+	if c.encryptor == nil {
+		return nil, errors.New("encryption key is required to store payloads")
+	}
+	encrypted, err := c.encryptor.Encrypt(ctx, b)
+	if err != nil {
+		return nil, err
+	}
+	ref, err := c.payloadStore.Put(ctx, []byte(encrypted))
+	if err != nil {
+		return nil, fmt.Errorf("failed to store payload: %w", err)
+	}
+	return encodePayload(payloadEnvelope{Ref: ref})
+}
+
+// Fetches the payload from the store if the envelope is a reference to one.
+func (c *Client) fetchPayload(ctx context.Context, env payloadEnvelope) (payloadEnvelope, error) {
+	if env.Ref == "" {
+		return env, nil
+	}
+	if c.payloadStore == nil {
+		return env, errors.New("payload is in a store but no payload store is set")
+	}
+	encrypted, err := c.payloadStore.Get(ctx, env.Ref)
+	if err != nil {
+		return env, fmt.Errorf("failed to fetch payload: %w", err)
+	}
+	b, err := c.encryptor.Decrypt(ctx, string(encrypted))
+	if err != nil {
+		return env, fmt.Errorf("%w: %w", errDecryptFailed, err)
+	}
+	return decodePayload(b)
+}
@@ -0,0 +1,27 @@
+package sdk
+
+import "time"
+
+// PreviewRuns is used to get when a job with the properties specified would next run if it
+// was created now, so that users can be shown the schedule before the job is created. Up to
+// n times are returned, fewer if the schedule ends first. Times are computed locally, so
+// schedules which are invalid or not understood by the SDK return nil.
+func PreviewRuns(props ScheduleJobPropertiesBuilder, n int) []time.Time {
+	now := time.Now()
+	if n <= 0 || props.validate(now, false) != nil {
+		return nil
+	}
+	first, next, err := runTimes(props, now)
+	if err != nil {
+		return nil
+	}
+	runs := []time.Time{first}
+	for len(runs) < n && next != nil {
+		t, ok := next(runs[len(runs)-1])
+		if !ok || !t.After(runs[len(runs)-1]) {
+			break
+		}
+		runs = append(runs, t)
+	}
+	return runs
+}
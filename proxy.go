@@ -0,0 +1,54 @@
+package sdk
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// WithProxy is used to send API requests through the forward proxy at the URL specified, such
+// as "http://proxy.internal:3128". A nil URL sends requests directly, ignoring the proxy
+// environment variables. Like WithDialContext, this only applies when the HTTP client uses a
+// *http.Transport and is replaced by a later call to SetClient.
+func WithProxy(proxyURL *url.URL) ServerOption {
+	return func(s *Server) {
+		s.client = withTransport(s.client, func(t *http.Transport) {
+			t.Proxy = http.ProxyURL(proxyURL)
+		})
+	}
+}
+
+// WithClientProxy is used to send API requests through the forward proxy at the URL
+// specified. A nil URL sends requests directly, ignoring the proxy environment variables. This
+// only applies when the HTTP client uses a *http.Transport.
+func WithClientProxy(proxyURL *url.URL) ClientOption {
+	return func(c *Client) {
+		c.client = withTransport(c.client, func(t *http.Transport) {
+			t.Proxy = http.ProxyURL(proxyURL)
+		})
+	}
+}
+
+// WithProxyFromEnvironment is used to pick the proxy API requests are sent through from the
+// HTTPS_PROXY and NO_PROXY environment variables. This is the default for DefaultClient, so
+// it is only needed when the HTTP client was built without it. Like WithDialContext, this only
+// applies when the HTTP client uses a *http.Transport and is replaced by a later call to
+// SetClient.
+func WithProxyFromEnvironment() ServerOption {
+	return func(s *Server) {
+		s.client = withTransport(s.client, func(t *http.Transport) {
+			t.Proxy = http.ProxyFromEnvironment
+		})
+	}
+}
+
+// WithClientProxyFromEnvironment is used to pick the proxy API requests are sent through from
+// the HTTPS_PROXY and NO_PROXY environment variables. This is the default for DefaultClient,
+// so it is only needed when the HTTP client was built without it. This only applies when the
+// HTTP client uses a *http.Transport.
+func WithClientProxyFromEnvironment() ClientOption {
+	return func(c *Client) {
+		c.client = withTransport(c.client, func(t *http.Transport) {
+			t.Proxy = http.ProxyFromEnvironment
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package sdk
+
+import (
+	"crypto/rand"
+	"io"
+)
+
+// WithRandom is used to set where the server reads the randomness for nonces and idempotency
+// keys from. This is intended for tests which need deterministic ciphertext, and must not be
+// set to anything predictable in production since reusing a nonce breaks the encryption.
+// Defaults to crypto/rand.Reader.
+func WithRandom(r io.Reader) ServerOption {
+	return func(s *Server) {
+		s.Client.random = r
+	}
+}
+
+// WithClientRandom is used to set where the client reads the randomness for nonces and
+// idempotency keys from. See WithRandom.
+func WithClientRandom(r io.Reader) ClientOption {
+	return func(c *Client) {
+		c.random = r
+	}
+}
+
+// Gets the randomness source of the client.
+func (c *Client) randomReader() io.Reader {
+	if c.random == nil {
+		return rand.Reader
+	}
+	return c.random
+}
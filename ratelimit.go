@@ -0,0 +1,78 @@
+package sdk
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimitError is returned when the API responds with 429 Too Many Requests. It unwraps to
+// the RequestError for the response.
+type RateLimitError struct {
+	RequestError
+
+	// Reset is when requests are allowed again, or the zero time if the API did not say.
+	Reset time.Time
+
+	// Limit and Remaining are from the X-RateLimit-Limit and X-RateLimit-Remaining headers,
+	// or -1 if they were not sent.
+	Limit     int
+	Remaining int
+}
+
+// Error is used to convert the rate limit error to a string.
+func (e RateLimitError) Error() string {
+	if e.Reset.IsZero() {
+		return "rate limited"
+	}
+	return "rate limited until " + e.Reset.Format(time.RFC3339)
+}
+
+// Unwrap is used to get the RequestError for the response.
+func (e RateLimitError) Unwrap() error {
+	return e.RequestError
+}
+
+// RetryAfter is used to get how long to wait before sending another request. This is zero if
+// the reset time is unknown or has passed.
+func (e RateLimitError) RetryAfter() time.Duration {
+	if e.Reset.IsZero() {
+		return 0
+	}
+	if d := time.Until(e.Reset); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// Parses an integer header, returning -1 if it is missing or invalid.
+func intHeader(h http.Header, key string) int {
+	n, err := strconv.Atoi(h.Get(key))
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// Builds the rate limit error from a 429 response. Retry-After is used for the reset time,
+// either as seconds or a HTTP date, falling back to the unix time in X-RateLimit-Reset.
+func newRateLimitError(resp *http.Response, req *http.Request, now time.Time) RateLimitError {
+	e := RateLimitError{
+		RequestError: RequestError{Status: resp.StatusCode, Request: req},
+		Limit:        intHeader(resp.Header, "X-RateLimit-Limit"),
+		Remaining:    intHeader(resp.Header, "X-RateLimit-Remaining"),
+	}
+	if ra := resp.Header.Get("Retry-After"); ra != "" {
+		if secs, err := strconv.Atoi(ra); err == nil {
+			e.Reset = now.Add(time.Duration(secs) * time.Second)
+		} else if t, err := http.ParseTime(ra); err == nil {
+			e.Reset = t
+		}
+	}
+	if e.Reset.IsZero() {
+		if unix, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			e.Reset = time.Unix(unix, 0)
+		}
+	}
+	return e
+}
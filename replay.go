@@ -0,0 +1,90 @@
+package sdk
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// ReplayStore is used to remember the deliveries a server has accepted so that a captured
+// request cannot be replayed whilst its timestamp is still fresh. Implement this on top of
+// a shared store such as Redis when running more than one instance of the server.
+type ReplayStore interface {
+	// Seen is used to record the key, returning true if it was already recorded. The key must
+	// be remembered for at least the TTL specified.
+	Seen(ctx context.Context, key string, ttl time.Duration) (bool, error)
+}
+
+// WithReplayStore is used to reject deliveries whose signature has already been seen.
+func WithReplayStore(store ReplayStore) ServerOption {
+	return func(s *Server) {
+		s.replay = store
+	}
+}
+
+type memoryReplayEntry struct {
+	key     string
+	expires time.Time
+}
+
+// MemoryReplayStore is an in-memory ReplayStore which holds up to a fixed number of keys,
+// evicting the least recently added key when it is full.
+type MemoryReplayStore struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+	now      func() time.Time
+}
+
+var _ ReplayStore = (*MemoryReplayStore)(nil)
+
+// NewMemoryReplayStore is used to create an in-memory replay store holding up to capacity
+// keys. The capacity should be more than the number of deliveries expected within the
+// timestamp window.
+func NewMemoryReplayStore(capacity int) *MemoryReplayStore {
+	if capacity < 1 {
+		panic("capacity must be at least 1")
+	}
+	return &MemoryReplayStore{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element, capacity),
+		order:    list.New(),
+		now:      time.Now,
+	}
+}
+
+// Seen implements ReplayStore.
+func (m *MemoryReplayStore) Seen(_ context.Context, key string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+
+	// Drop the expired keys from the front, which are the oldest.
+	for e := m.order.Front(); e != nil; e = m.order.Front() {
+		entry := e.Value.(*memoryReplayEntry)
+		if entry.expires.After(now) {
+			break
+		}
+		m.order.Remove(e)
+		delete(m.entries, entry.key)
+	}
+
+	if e, ok := m.entries[key]; ok {
+		if e.Value.(*memoryReplayEntry).expires.After(now) {
+			return true, nil
+		}
+		m.order.Remove(e)
+		delete(m.entries, key)
+	}
+
+	// Evict the oldest key if the store is full.
+	if m.order.Len() >= m.capacity {
+		e := m.order.Front()
+		m.order.Remove(e)
+		delete(m.entries, e.Value.(*memoryReplayEntry).key)
+	}
+	m.entries[key] = m.order.PushBack(&memoryReplayEntry{key: key, expires: now.Add(ttl)})
+	return false, nil
+}
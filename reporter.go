@@ -0,0 +1,66 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+)
+
+// ErrorReport defines a panic or an error returned by a handler which is forwarded to the
+// error reporter.
+type ErrorReport struct {
+	// Route is the route of the job.
+	Route string
+
+	// Job is the job that was being run.
+	Job JobContext
+
+	// Err is the error returned by the handler. If the handler panicked, it describes the
+	// value it panicked with.
+	Err error
+
+	// Panic is the value the handler panicked with, or nil if it returned an error.
+	Panic any
+
+	// Stack is the stack trace of the goroutine at the point it panicked, or nil if the
+	// handler returned an error.
+	Stack []byte
+}
+
+// Reporter is used to forward the panics and errors of handlers to an error reporting service.
+// See the reporting/sentryadapter module for a Sentry implementation.
+type Reporter interface {
+	// Report is called with the details of the failure. The context is the one the handler
+	// was called with.
+	Report(ctx context.Context, report ErrorReport)
+}
+
+// WithErrorReporter is used to set the reporter that panics and errors returned by handlers
+// are forwarded to. It is called in addition to the error and panic handlers.
+func WithErrorReporter(r Reporter) ServerOption {
+	return func(s *Server) {
+		s.reporter = r
+	}
+}
+
+// Forwards a handler error to the reporter if one is set.
+func (s *Server) reportError(ctx context.Context, route string, err error) {
+	s.report(ctx, ErrorReport{Route: route, Err: err})
+}
+
+// Forwards a handler panic to the reporter if one is set.
+func (s *Server) reportPanic(ctx context.Context, route string, val any, stack []byte) {
+	s.report(ctx, ErrorReport{
+		Route: route, Err: fmt.Errorf("panic: %v", val), Panic: val, Stack: stack,
+	})
+}
+
+// Calls the reporter with the job from the context, making sure a panic in it does not escape.
+func (s *Server) report(ctx context.Context, report ErrorReport) {
+	if s.reporter == nil {
+		return
+	}
+	report.Job, _ = JobFromContext(ctx)
+	if v := panicCondom(func() { s.reporter.Report(ctx, report) }); v != nil {
+		s.logger.Error("error reporter panicked", "route", report.Route, "panic", v)
+	}
+}
@@ -0,0 +1,64 @@
+package sdk_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"go.clocktick.dev/sdk"
+)
+
+// Records the reports it is given.
+type recordingReporter struct {
+	mu      sync.Mutex
+	reports []sdk.ErrorReport
+}
+
+func (r *recordingReporter) Report(_ context.Context, report sdk.ErrorReport) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reports = append(r.reports, report)
+}
+
+func TestErrorReporterGetsHandlerFailures(t *testing.T) {
+	api := newRecordingAPI(t)
+	rep := &recordingReporter{}
+	s, signer := newTestServer(t, api.URL, sdk.WithErrorReporter(rep), sdk.WithPanicHandler(func(any) {}))
+	failing := sdk.AddRoute1(s, "failing", func(context.Context, string) error {
+		return errors.New("boom")
+	})
+	panicking := sdk.AddRoute1(s, "panicking", func(context.Context, string) error {
+		panic("oh no")
+	})
+	ctx := context.Background()
+
+	if _, err := failing.Schedule(ctx, sdk.FromNow().Minutes(1), "a"); err != nil {
+		t.Fatal(err)
+	}
+	deliverCreated(t, s, signer, api.last(t), "job_1")
+	if _, err := panicking.Schedule(ctx, sdk.FromNow().Minutes(1), "b"); err != nil {
+		t.Fatal(err)
+	}
+	deliverCreated(t, s, signer, api.last(t), "job_2")
+
+	rep.mu.Lock()
+	defer rep.mu.Unlock()
+	if len(rep.reports) != 2 {
+		t.Fatalf("got %d reports, want 2", len(rep.reports))
+	}
+	got := rep.reports[0]
+	if got.Route != "failing" || got.Job.JobID != "job_1" || got.Err == nil || got.Err.Error() != "boom" {
+		t.Errorf("error report = %+v", got)
+	}
+	if got.Panic != nil || got.Stack != nil {
+		t.Errorf("error report has a panic: %v", got.Panic)
+	}
+	got = rep.reports[1]
+	if got.Route != "panicking" || got.Job.JobID != "job_2" || got.Panic != "oh no" {
+		t.Errorf("panic report = %+v", got)
+	}
+	if len(got.Stack) == 0 {
+		t.Error("panic report has no stack")
+	}
+}
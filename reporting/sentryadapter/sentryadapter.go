@@ -0,0 +1,65 @@
+// Package sentryadapter is used to report the panics and errors of Clocktick handlers to Sentry.
+package sentryadapter
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/getsentry/sentry-go"
+	"go.clocktick.dev/sdk"
+)
+
+type reporter struct {
+	hub *sentry.Hub
+}
+
+// Gets the hub for the context, falling back to the one the reporter was created with.
+func (r reporter) hubFor(ctx context.Context) *sentry.Hub {
+	if hub := sentry.GetHubFromContext(ctx); hub != nil {
+		return hub.Clone()
+	}
+	return r.hub.Clone()
+}
+
+func (r reporter) Report(ctx context.Context, report sdk.ErrorReport) {
+	hub := r.hubFor(ctx)
+	hub.ConfigureScope(func(scope *sentry.Scope) {
+		scope.SetTag("clocktick.route", report.Route)
+		if report.Job.JobID != "" {
+			scope.SetTag("clocktick.job_id", report.Job.JobID)
+		}
+		job := sentry.Context{
+			"route":   report.Route,
+			"job_id":  report.Job.JobID,
+			"attempt": strconv.Itoa(report.Job.Attempt),
+		}
+		if report.Job.CustomID != "" {
+			job["custom_id"] = report.Job.CustomID
+		}
+		if report.Job.EndpointID != "" {
+			job["endpoint_id"] = report.Job.EndpointID
+		}
+		if !report.Job.ScheduledAt.IsZero() {
+			job["scheduled_at"] = report.Job.ScheduledAt
+		}
+		if report.Stack != nil {
+			job["stack"] = string(report.Stack)
+		}
+		scope.SetContext("clocktick", job)
+	})
+	if report.Panic != nil {
+		hub.RecoverWithContext(ctx, report.Panic)
+		return
+	}
+	hub.CaptureException(report.Err)
+}
+
+// New is used to create a sdk.Reporter which sends panics and errors to the Sentry hub
+// specified, tagged with the route and job ID. If the context of the handler carries a hub, it
+// is used instead. If hub is nil, the current hub is used.
+func New(hub *sentry.Hub) sdk.Reporter {
+	if hub == nil {
+		hub = sentry.CurrentHub()
+	}
+	return reporter{hub: hub}
+}
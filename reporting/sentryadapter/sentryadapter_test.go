@@ -0,0 +1,78 @@
+package sentryadapter_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"go.clocktick.dev/sdk"
+	"go.clocktick.dev/sdk/reporting/sentryadapter"
+)
+
+// Records the events sent to Sentry instead of sending them.
+type recordingTransport struct {
+	mu     sync.Mutex
+	events []*sentry.Event
+}
+
+func (t *recordingTransport) Flush(time.Duration) bool       { return true }
+func (t *recordingTransport) Configure(sentry.ClientOptions) {}
+
+func (t *recordingTransport) SendEvent(e *sentry.Event) {
+	t.mu.Lock()
+	t.events = append(t.events, e)
+	t.mu.Unlock()
+}
+
+// Creates a hub which records its events in the transport returned.
+func newHub(t *testing.T) (*sentry.Hub, *recordingTransport) {
+	t.Helper()
+	transport := &recordingTransport{}
+	client, err := sentry.NewClient(sentry.ClientOptions{Transport: transport})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sentry.NewHub(client, sentry.NewScope()), transport
+}
+
+func TestReportsErrorsWithJobTags(t *testing.T) {
+	hub, transport := newHub(t)
+	sentryadapter.New(hub).Report(context.Background(), sdk.ErrorReport{
+		Route: "email",
+		Job:   sdk.JobContext{JobID: "job_1", Attempt: 2},
+		Err:   errors.New("smtp down"),
+	})
+
+	if len(transport.events) != 1 {
+		t.Fatalf("got %d events, want 1", len(transport.events))
+	}
+	e := transport.events[0]
+	if e.Tags["clocktick.route"] != "email" || e.Tags["clocktick.job_id"] != "job_1" {
+		t.Errorf("tags = %v", e.Tags)
+	}
+	if len(e.Exception) == 0 || e.Exception[len(e.Exception)-1].Value != "smtp down" {
+		t.Errorf("exception = %v, want smtp down", e.Exception)
+	}
+
+	// The tags are set on a clone, so the hub passed in is left alone.
+	hub.CaptureMessage("other")
+	if got := transport.events[1].Tags["clocktick.route"]; got != "" {
+		t.Errorf("hub was tagged with route %q", got)
+	}
+}
+
+func TestReportsPanics(t *testing.T) {
+	hub, transport := newHub(t)
+	sentryadapter.New(hub).Report(context.Background(), sdk.ErrorReport{
+		Route: "email",
+		Err:   errors.New("panic: boom"),
+		Panic: "boom",
+	})
+
+	if len(transport.events) != 1 || transport.events[0].Message != "boom" {
+		t.Errorf("events = %v, want the panic message boom", transport.events)
+	}
+}
@@ -0,0 +1,37 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"time"
+)
+
+// The body of a successful delivery response when the handler returned a result.
+type jobResultBody struct {
+	Result any `json:"result"`
+}
+
+// JobRunResult defines the result a handler returned for the most recent run of a job.
+type JobRunResult struct {
+	JobID string `json:"job_id"`
+
+	// Result is the JSON encoded value the handler returned. Use json.Unmarshal to read it.
+	Result json.RawMessage `json:"result"`
+
+	// CompletedAt is when the run the result is from finished.
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// GetJobResult is used to get the result returned by the handler for the most recent run of
+// the job with the ID specified.
+func (c *Client) GetJobResult(ctx context.Context, jobId string) (JobRunResult, error) {
+	if jobId == "" {
+		return JobRunResult{}, errors.New("job ID is required")
+	}
+	var result JobRunResult
+	reqUrl := c.baseURL + jobsPath + "/" + url.PathEscape(jobId) + "/result"
+	err := c.send(ctx, "GET", reqUrl, nil, &result)
+	return result, err
+}
@@ -0,0 +1,38 @@
+package sdk
+
+import (
+	"strconv"
+	"time"
+)
+
+// RetryError is returned from a handler to ask for the job to be re-delivered after a delay.
+// Any other error from a handler is also retried, but without a Retry-After hint.
+type RetryError struct {
+	After time.Duration
+	Err   error
+}
+
+// Error is used to convert the retry error to a string.
+func (e *RetryError) Error() string {
+	return "retry after " + e.After.String() + ": " + e.Err.Error()
+}
+
+// Unwrap is used to get the underlying handler error.
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// RetryAfter is used to wrap a handler error so the job is re-delivered no sooner than the
+// duration specified.
+func RetryAfter(d time.Duration, err error) error {
+	return &RetryError{After: d, Err: err}
+}
+
+// Formats the duration as a Retry-After header value in whole seconds, rounding up.
+func retryAfterSeconds(d time.Duration) string {
+	secs := (d + time.Second - 1) / time.Second
+	if secs < 1 {
+		secs = 1
+	}
+	return strconv.FormatInt(int64(secs), 10)
+}
@@ -0,0 +1,120 @@
+//go:build !tinygo
+
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// AddRoute is used to add a route to the server. f MUST be a function that takes in a
+// context.Context and any other number of arguments, and returns either nothing, an error, or
+// a result and an error. A non-nil error causes the job to be re-delivered (see RetryAfter).
+// A non-nil result is sent back to the platform and can be read with GetJobResult. AddRoute
+// panics if f is not valid, a parameter cannot be encoded, such as a channel, a function, or
+// a struct with no exported fields, or the route has already been added. Use AddRouteE to
+// handle this as an error instead. This uses reflection to call f, so it is not available
+// under TinyGo where AddDispatcher should be used instead.
+func (s *Server) AddRoute(route string, f any, opts ...Option) {
+	if err := s.AddRouteE(route, f, opts...); err != nil {
+		panic(err)
+	}
+}
+
+// AddRouteE is used to add a route to the server in the same way as AddRoute, returning an
+// error if f is not valid or the route has already been added rather than panicking.
+func (s *Server) AddRouteE(route string, f any, opts ...Option) error {
+	// Validate the function.
+	fv := reflect.ValueOf(f)
+	if fv.Kind() != reflect.Func {
+		return errors.New("f must be a function")
+	}
+	ft := fv.Type()
+	if ft.NumIn() < 1 || ft.In(0) != contextType {
+		return errors.New("f must take in a context.Context as the first argument")
+	}
+	numOut := ft.NumOut()
+	if numOut > 2 || (numOut != 0 && ft.Out(numOut-1) != errorType) {
+		return errors.New("f must return nothing, an error, or a result and an error")
+	}
+
+	// Resolve the parameter types once so scheduling and delivery do not have to walk the
+	// function type on every call, checking they can be sent in the payload.
+	params := make([]reflect.Type, ft.NumIn()-1)
+	for i := range params {
+		params[i] = ft.In(i + 1)
+		if err := checkEncodable(params[i], map[reflect.Type]bool{}); err != nil {
+			return fmt.Errorf("parameter %d of route %q cannot be sent: %w", i, route, err)
+		}
+	}
+
+	// Build the dispatcher that calls the function with the context and the arguments.
+	call := func(ctx context.Context, raws []msgpack.RawMessage) (any, error) {
+		in := make([]reflect.Value, 1, len(raws)+1)
+		in[0] = reflect.ValueOf(ctx)
+		in, err := decodeArgs(in, params, raws)
+		if err != nil {
+			return nil, err
+		}
+		out := fv.Call(in)
+		if numOut == 0 {
+			return nil, nil
+		}
+		if errV := out[numOut-1]; !errV.IsNil() {
+			return nil, errV.Interface().(error)
+		}
+		if numOut == 2 && !isNilValue(out[0]) {
+			return out[0].Interface(), nil
+		}
+		return nil, nil
+	}
+	check := func(raws []msgpack.RawMessage) error {
+		_, err := decodeArgs(nil, params, raws)
+		return err
+	}
+
+	// Add the function to the map.
+	return s.addRoute(route, funcOpts{
+		f: f, numArgs: len(params), call: call, check: check, a: opts,
+	})
+}
+
+// Checks if the value is a nil pointer, interface, map, or slice.
+func isNilValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+		return v.IsNil()
+	}
+	return false
+}
+
+var (
+	rawMessageType = reflect.TypeOf(msgpack.RawMessage(nil))
+	errorType      = reflect.TypeOf((*error)(nil)).Elem()
+	contextType    = reflect.TypeOf((*context.Context)(nil)).Elem()
+)
+
+// Decodes the raw arguments into the given parameter types and appends them to args.
+// Parameters declared as msgpack.RawMessage are passed the raw argument as is.
+func decodeArgs(args []reflect.Value, params []reflect.Type, raws []msgpack.RawMessage) ([]reflect.Value, error) {
+	for i, raw := range raws {
+		pt := params[i]
+		if pt == rawMessageType {
+			args = append(args, reflect.ValueOf(raw))
+			continue
+		}
+		v := reflect.New(pt)
+		if len(raw) != 0 {
+			// A missing argument is left as the zero value.
+			if err := msgpack.Unmarshal(raw, v.Interface()); err != nil {
+				return nil, &ArgumentError{Index: i, Err: err}
+			}
+		}
+		args = append(args, v.Elem())
+	}
+	return args, nil
+}
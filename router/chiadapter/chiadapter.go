@@ -0,0 +1,15 @@
+// Package chiadapter is used to mount the delivery endpoint of the Clocktick SDK in a chi
+// router.
+package chiadapter
+
+import (
+	"github.com/go-chi/chi/v5"
+	"go.clocktick.dev/sdk"
+)
+
+// Mount is used to handle deliveries to the server on the pattern specified. Middleware of
+// the router runs before the delivery is handled, and values it adds to the request context
+// are in the context passed to the job handlers.
+func Mount(r chi.Router, pattern string, s *sdk.Server) {
+	r.Method("POST", pattern, s.Handler())
+}
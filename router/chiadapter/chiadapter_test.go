@@ -0,0 +1,61 @@
+package chiadapter_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.clocktick.dev/sdk"
+	"go.clocktick.dev/sdk/router/chiadapter"
+	"go.clocktick.dev/sdk/sdktest"
+)
+
+type userKey struct{}
+
+func TestMountRunsDeliveries(t *testing.T) {
+	pub, priv, err := sdktest.GenerateKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := sdk.NewServerE("", "test-encryption-key", pub, "endpoint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	sdk.AddRoute1(s, "greet", func(ctx context.Context, name string) error {
+		got = append(got, name+" from "+ctx.Value(userKey{}).(string))
+		return nil
+	})
+
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), userKey{}, "middleware")))
+		})
+	})
+	chiadapter.Mount(r, "/clocktick", s)
+
+	signer, err := sdktest.NewSigner(priv, "test-encryption-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := signer.Body("greet", "job_1", "ada")
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := signer.Sign("/clocktick", body, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", w.Code, w.Body)
+	}
+	if len(got) != 1 || got[0] != "ada from middleware" {
+		t.Errorf("handler got %v, want [ada from middleware]", got)
+	}
+}
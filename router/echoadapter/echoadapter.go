@@ -0,0 +1,32 @@
+// Package echoadapter is used to mount the delivery endpoint of the Clocktick SDK in an echo
+// server.
+package echoadapter
+
+import (
+	"context"
+
+	"github.com/labstack/echo/v4"
+	"go.clocktick.dev/sdk"
+)
+
+type echoContextKey struct{}
+
+// Handler is used to get an echo handler for deliveries to the server. Mount it with POST.
+// The echo context is passed on to the job handlers and can be fetched with Context.
+func Handler(s *sdk.Server) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		r := c.Request()
+		ctx := context.WithValue(r.Context(), echoContextKey{}, c)
+		s.ServeHTTP(c.Response(), r.WithContext(ctx))
+		return nil
+	}
+}
+
+// Context is used to get the echo context of the delivery from the context passed to a job
+// handler, so that values set by echo middleware can be read. Returns nil if the delivery did
+// not come through Handler. Do not use it in jobs run with sdk.WithAsyncExecution, since echo
+// reuses the context once the request has finished.
+func Context(ctx context.Context) echo.Context {
+	c, _ := ctx.Value(echoContextKey{}).(echo.Context)
+	return c
+}
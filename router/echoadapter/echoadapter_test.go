@@ -0,0 +1,66 @@
+package echoadapter_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.clocktick.dev/sdk"
+	"go.clocktick.dev/sdk/router/echoadapter"
+	"go.clocktick.dev/sdk/sdktest"
+)
+
+func TestHandlerPassesEchoContext(t *testing.T) {
+	pub, priv, err := sdktest.GenerateKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := sdk.NewServerE("", "test-encryption-key", pub, "endpoint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	sdk.AddRoute1(s, "greet", func(ctx context.Context, name string) error {
+		got = append(got, name+" from "+echoadapter.Context(ctx).Get("user").(string))
+		return nil
+	})
+
+	e := echo.New()
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set("user", "middleware")
+			return next(c)
+		}
+	})
+	e.POST("/clocktick", echoadapter.Handler(s))
+
+	signer, err := sdktest.NewSigner(priv, "test-encryption-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := signer.Body("greet", "job_1", "ada")
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := signer.Sign("/clocktick", body, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", w.Code, w.Body)
+	}
+	if len(got) != 1 || got[0] != "ada from middleware" {
+		t.Errorf("handler got %v, want [ada from middleware]", got)
+	}
+}
+
+func TestContextOutsideHandler(t *testing.T) {
+	if c := echoadapter.Context(context.Background()); c != nil {
+		t.Errorf("Context = %v, want nil", c)
+	}
+}
@@ -0,0 +1,30 @@
+// Package ginadapter is used to mount the delivery endpoint of the Clocktick SDK in a gin
+// engine.
+package ginadapter
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"go.clocktick.dev/sdk"
+)
+
+type ginContextKey struct{}
+
+// Handler is used to get a gin handler for deliveries to the server. Mount it with POST. The
+// gin context is passed on to the job handlers and can be fetched with Context.
+func Handler(s *sdk.Server) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := context.WithValue(c.Request.Context(), ginContextKey{}, c)
+		s.ServeHTTP(c.Writer, c.Request.WithContext(ctx))
+	}
+}
+
+// Context is used to get the gin context of the delivery from the context passed to a job
+// handler, so that values set by gin middleware can be read. Returns nil if the delivery did
+// not come through Handler. Do not use it in jobs run with sdk.WithAsyncExecution, since gin
+// reuses the context once the request has finished.
+func Context(ctx context.Context) *gin.Context {
+	c, _ := ctx.Value(ginContextKey{}).(*gin.Context)
+	return c
+}
@@ -0,0 +1,62 @@
+package ginadapter_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.clocktick.dev/sdk"
+	"go.clocktick.dev/sdk/router/ginadapter"
+	"go.clocktick.dev/sdk/sdktest"
+)
+
+func TestHandlerPassesGinContext(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	pub, priv, err := sdktest.GenerateKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := sdk.NewServerE("", "test-encryption-key", pub, "endpoint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	sdk.AddRoute1(s, "greet", func(ctx context.Context, name string) error {
+		got = append(got, name+" from "+ginadapter.Context(ctx).GetString("user"))
+		return nil
+	})
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) { c.Set("user", "middleware") })
+	r.POST("/clocktick", ginadapter.Handler(s))
+
+	signer, err := sdktest.NewSigner(priv, "test-encryption-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := signer.Body("greet", "job_1", "ada")
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := signer.Sign("/clocktick", body, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", w.Code, w.Body)
+	}
+	if len(got) != 1 || got[0] != "ada from middleware" {
+		t.Errorf("handler got %v, want [ada from middleware]", got)
+	}
+}
+
+func TestContextOutsideHandler(t *testing.T) {
+	if c := ginadapter.Context(context.Background()); c != nil {
+		t.Errorf("Context = %v, want nil", c)
+	}
+}
@@ -0,0 +1,485 @@
+package sdk
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Defines how often an RRULE repeats.
+type rruleFreq int
+
+const (
+	rruleYearly rruleFreq = iota
+	rruleMonthly
+	rruleWeekly
+	rruleDaily
+	rruleHourly
+	rruleMinutely
+)
+
+var rruleFreqs = map[string]rruleFreq{
+	"YEARLY":   rruleYearly,
+	"MONTHLY":  rruleMonthly,
+	"WEEKLY":   rruleWeekly,
+	"DAILY":    rruleDaily,
+	"HOURLY":   rruleHourly,
+	"MINUTELY": rruleMinutely,
+}
+
+var rruleWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// Defines a day in BYDAY. n is the occurrence of the weekday within the month or year, where
+// negative values count from the end and 0 matches every occurrence.
+type rruleDay struct {
+	weekday time.Weekday
+	n       int
+}
+
+// Defines a parsed RRULE. The BY* fields are sorted and empty when not set.
+type rrule struct {
+	freq     rruleFreq
+	interval int
+	count    int
+	until    time.Time
+	wkst     time.Weekday
+
+	byMonth, byMonthDay, byHour, byMinute, bySecond, bySetPos []int
+	byDay                                                     []rruleDay
+}
+
+// Parses a comma separated list of integers, checking each is within the range. If negative
+// is true, values counting back from the end down to -max are also allowed.
+func parseRRuleInts(name, s string, min, max int, negative bool) ([]int, error) {
+	var values []int
+	for _, part := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(part)
+		inRange := (n >= min && n <= max) || (negative && n < 0 && n >= -max)
+		if err != nil || !inRange {
+			return nil, fmt.Errorf("invalid %s value %q", name, part)
+		}
+		values = append(values, n)
+	}
+	sort.Ints(values)
+	return values, nil
+}
+
+// Parses the UNTIL value, which is either a date or a date and time.
+func parseRRuleUntil(s string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405", "20060102"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			if layout == "20060102" {
+				// A date includes the whole day.
+				t = t.Add(24*time.Hour - time.Second)
+			}
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid UNTIL value %q", s)
+}
+
+// Parses an RRULE as defined in RFC 5545. BYYEARDAY, BYWEEKNO, and FREQ=SECONDLY are not
+// supported.
+func parseRRule(s string) (*rrule, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if s == "" {
+		return nil, errors.New("RRULE is empty")
+	}
+	r := &rrule{freq: -1, interval: 1, wkst: time.Monday}
+	seen := map[string]bool{}
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		key = strings.ToUpper(key)
+		if !ok || value == "" {
+			return nil, fmt.Errorf("invalid RRULE part %q", part)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("RRULE has %s more than once", key)
+		}
+		seen[key] = true
+
+		var err error
+		value = strings.ToUpper(value)
+		switch key {
+		case "FREQ":
+			f, ok := rruleFreqs[value]
+			if !ok {
+				return nil, fmt.Errorf("unsupported FREQ %q", value)
+			}
+			r.freq = f
+		case "INTERVAL":
+			r.interval, err = strconv.Atoi(value)
+			if err != nil || r.interval < 1 {
+				return nil, fmt.Errorf("invalid INTERVAL %q", value)
+			}
+		case "COUNT":
+			r.count, err = strconv.Atoi(value)
+			if err != nil || r.count < 1 {
+				return nil, fmt.Errorf("invalid COUNT %q", value)
+			}
+		case "UNTIL":
+			r.until, err = parseRRuleUntil(value)
+		case "WKST":
+			d, ok := rruleWeekdays[value]
+			if !ok {
+				return nil, fmt.Errorf("invalid WKST %q", value)
+			}
+			r.wkst = d
+		case "BYMONTH":
+			r.byMonth, err = parseRRuleInts(key, value, 1, 12, false)
+		case "BYMONTHDAY":
+			r.byMonthDay, err = parseRRuleInts(key, value, 1, 31, true)
+		case "BYHOUR":
+			r.byHour, err = parseRRuleInts(key, value, 0, 23, false)
+		case "BYMINUTE":
+			r.byMinute, err = parseRRuleInts(key, value, 0, 59, false)
+		case "BYSECOND":
+			r.bySecond, err = parseRRuleInts(key, value, 0, 59, false)
+		case "BYSETPOS":
+			r.bySetPos, err = parseRRuleInts(key, value, 1, 366, true)
+		case "BYDAY":
+			for _, d := range strings.Split(value, ",") {
+				if len(d) < 2 {
+					return nil, fmt.Errorf("invalid BYDAY value %q", d)
+				}
+				wd, ok := rruleWeekdays[d[len(d)-2:]]
+				if !ok {
+					return nil, fmt.Errorf("invalid BYDAY value %q", d)
+				}
+				n := 0
+				if prefix := d[:len(d)-2]; prefix != "" {
+					n, err = strconv.Atoi(prefix)
+					if err != nil || n == 0 || n < -53 || n > 53 {
+						return nil, fmt.Errorf("invalid BYDAY value %q", d)
+					}
+				}
+				r.byDay = append(r.byDay, rruleDay{weekday: wd, n: n})
+			}
+		default:
+			return nil, fmt.Errorf("unsupported RRULE part %s", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if r.freq == -1 {
+		return nil, errors.New("RRULE has no FREQ")
+	}
+	if r.count != 0 && !r.until.IsZero() {
+		return nil, errors.New("RRULE cannot have both COUNT and UNTIL")
+	}
+	for _, d := range r.byDay {
+		if d.n != 0 && r.freq != rruleMonthly && r.freq != rruleYearly {
+			return nil, errors.New("BYDAY can only have an ordinal with FREQ=MONTHLY or FREQ=YEARLY")
+		}
+	}
+	if len(r.byMonthDay) != 0 && r.freq == rruleWeekly {
+		return nil, errors.New("BYMONTHDAY cannot be used with FREQ=WEEKLY")
+	}
+	return r, nil
+}
+
+// Gets the start of the period the index specified is in, counting from the start time.
+func (r *rrule) periodStart(start time.Time, i int) time.Time {
+	y, m, d := start.Date()
+	loc := start.Location()
+	n := i * r.interval
+	switch r.freq {
+	case rruleYearly:
+		return time.Date(y+n, time.January, 1, 0, 0, 0, 0, loc)
+	case rruleMonthly:
+		return time.Date(y, m+time.Month(n), 1, 0, 0, 0, 0, loc)
+	case rruleWeekly:
+		back := (int(start.Weekday()) - int(r.wkst) + 7) % 7
+		return time.Date(y, m, d-back+n*7, 0, 0, 0, 0, loc)
+	case rruleDaily:
+		return time.Date(y, m, d+n, 0, 0, 0, 0, loc)
+	case rruleHourly:
+		return time.Date(y, m, d, start.Hour()+n, 0, 0, 0, loc)
+	default:
+		return time.Date(y, m, d, start.Hour(), start.Minute()+n, 0, 0, loc)
+	}
+}
+
+// Gets the days in the period which match the rule.
+func (r *rrule) days(start, period time.Time) []time.Time {
+	var from, to time.Time
+	switch r.freq {
+	case rruleYearly:
+		from, to = period, period.AddDate(1, 0, 0)
+	case rruleMonthly:
+		from, to = period, period.AddDate(0, 1, 0)
+	case rruleWeekly:
+		from, to = period, period.AddDate(0, 0, 7)
+	default:
+		y, m, d := period.Date()
+		from = time.Date(y, m, d, 0, 0, 0, 0, period.Location())
+		to = from.AddDate(0, 0, 1)
+	}
+
+	var days []time.Time
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		if r.dayMatches(start, day) {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+// Checks if the day matches the BY* parts of the rule, or the start day when they are not
+// set.
+func (r *rrule) dayMatches(start, day time.Time) bool {
+	if len(r.byMonth) != 0 && !containsInt(r.byMonth, int(day.Month())) {
+		return false
+	}
+	lastDay := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, day.Location()).Day()
+	if len(r.byMonthDay) != 0 &&
+		!containsInt(r.byMonthDay, day.Day()) && !containsInt(r.byMonthDay, day.Day()-lastDay-1) {
+		return false
+	}
+	if len(r.byDay) != 0 && !r.weekdayMatches(day) {
+		return false
+	}
+
+	// Without the BY* parts, the day is taken from the start.
+	if len(r.byMonthDay) == 0 && len(r.byDay) == 0 {
+		switch r.freq {
+		case rruleYearly:
+			if len(r.byMonth) == 0 && day.Month() != start.Month() {
+				return false
+			}
+			return day.Day() == start.Day()
+		case rruleMonthly:
+			return day.Day() == start.Day()
+		case rruleWeekly:
+			return day.Weekday() == start.Weekday()
+		}
+	}
+	return true
+}
+
+// Checks if the day matches BYDAY. Ordinals count within the month, or the year when the
+// rule is yearly without BYMONTH.
+func (r *rrule) weekdayMatches(day time.Time) bool {
+	var index, remaining int
+	if r.freq == rruleYearly && len(r.byMonth) == 0 {
+		index = day.YearDay() - 1
+		remaining = time.Date(day.Year(), 12, 31, 0, 0, 0, 0, day.Location()).YearDay() - day.YearDay()
+	} else {
+		index = day.Day() - 1
+		remaining = time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, day.Location()).Day() - day.Day()
+	}
+	for _, d := range r.byDay {
+		if d.weekday != day.Weekday() {
+			continue
+		}
+		if d.n == 0 || d.n == index/7+1 || d.n == -(remaining/7+1) {
+			return true
+		}
+	}
+	return false
+}
+
+// Gets the values of a time part, which are the BY* values, filtered to the period value for
+// rules repeating at least as often as the part, or the start value otherwise.
+func rruleTimeValues(by []int, period, start int, fromPeriod bool) []int {
+	switch {
+	case fromPeriod && len(by) != 0 && !containsInt(by, period):
+		return nil
+	case fromPeriod:
+		return []int{period}
+	case len(by) != 0:
+		return by
+	}
+	return []int{start}
+}
+
+// Gets the runs within the period, in order.
+func (r *rrule) occurrences(start, period time.Time) []time.Time {
+	hours := rruleTimeValues(r.byHour, period.Hour(), start.Hour(), r.freq >= rruleHourly)
+	minutes := rruleTimeValues(r.byMinute, period.Minute(), start.Minute(), r.freq >= rruleMinutely)
+	seconds := rruleTimeValues(r.bySecond, 0, start.Second(), false)
+
+	var runs []time.Time
+	for _, day := range r.days(start, period) {
+		y, m, d := day.Date()
+		for _, h := range hours {
+			for _, mi := range minutes {
+				for _, sec := range seconds {
+					runs = append(runs, time.Date(y, m, d, h, mi, sec, 0, start.Location()))
+				}
+			}
+		}
+	}
+
+	if len(r.bySetPos) == 0 {
+		return runs
+	}
+	var picked []time.Time
+	for i, t := range runs {
+		if containsInt(r.bySetPos, i+1) || containsInt(r.bySetPos, i-len(runs)) {
+			picked = append(picked, t)
+		}
+	}
+	return picked
+}
+
+// Gets the first run of the rule after the time specified, where the series starts at start.
+// This returns false if the rule has ended or nothing matches within the next 8 years.
+func (r *rrule) next(start, after time.Time) (time.Time, bool) {
+	limit := after.AddDate(8, 0, 0)
+	count := 0
+	for i := 0; ; i++ {
+		period := r.periodStart(start, i)
+		if period.After(limit) {
+			return time.Time{}, false
+		}
+		for _, t := range r.occurrences(start, period) {
+			if t.Before(start) {
+				continue
+			}
+			if !r.until.IsZero() && t.After(r.until) {
+				return time.Time{}, false
+			}
+			count++
+			if r.count != 0 && count > r.count {
+				return time.Time{}, false
+			}
+			if t.After(after) {
+				return t, true
+			}
+		}
+	}
+}
+
+// Checks if the slice contains the value.
+func containsInt(s []int, v int) bool {
+	for _, n := range s {
+		if n == v {
+			return true
+		}
+	}
+	return false
+}
+
+type startFromRRule struct {
+	Type     string `json:"type"`
+	Rule     string `json:"rule"`
+	DateTime string `json:"datetime,omitempty"`
+}
+
+// FromRRulePropertiesBuilder is used to create a builder for properties.
+type FromRRulePropertiesBuilder struct {
+	rule       string
+	start      time.Time
+	id         string
+	headers    map[string]string
+	timezone   string
+	idemKey    string
+	exclusions exclusions
+	labels     labels
+	conflict   conflictPolicy
+}
+
+// FromRRule is used to create a builder for scheduling a job with an iCalendar RRULE as
+// defined in RFC 5545, such as "FREQ=MONTHLY;BYDAY=-1FR" for the last Friday of every month.
+// This can express recurrences which deltas and cron expressions cannot. The series starts
+// when the job is created unless Start is used. BYYEARDAY, BYWEEKNO, and FREQ=SECONDLY are not
+// supported. Use COUNT and UNTIL within the rule to end the series.
+func FromRRule(rule string) FromRRulePropertiesBuilder {
+	return FromRRulePropertiesBuilder{rule: rule}
+}
+
+// Start is used to set the start of the series, which is DTSTART in RFC 5545. Parts of the
+// rule which are not set, such as the time of day, are taken from it.
+func (p FromRRulePropertiesBuilder) Start(t time.Time) FromRRulePropertiesBuilder {
+	p.start = t
+	return p
+}
+
+// CustomID is used to set the custom ID of the job.
+func (p FromRRulePropertiesBuilder) CustomID(id string) FromRRulePropertiesBuilder {
+	p.id = id
+	return p
+}
+
+// Header is used to attach a custom header to the job. Headers are sent back unencrypted
+// on delivery as X-Clocktick-Header-<key> so that they can be read before decryption.
+func (p FromRRulePropertiesBuilder) Header(key, value string) FromRRulePropertiesBuilder {
+	p.headers = withHeader(p.headers, key, value)
+	return p
+}
+
+// Timezone is used to set the IANA timezone the rule is evaluated in, so that runs stay at
+// the same wall-clock time across daylight saving changes. If not set, UTC is used.
+func (p FromRRulePropertiesBuilder) Timezone(tz string) FromRRulePropertiesBuilder {
+	p.timezone = tz
+	return p
+}
+
+// IdempotencyKey is used to set the key the job is created with. The API will not create a
+// second job with the same key, so the request is retried if it fails with a transient error.
+func (p FromRRulePropertiesBuilder) IdempotencyKey(key string) FromRRulePropertiesBuilder {
+	p.idemKey = key
+	return p
+}
+
+// Exclude is used to defer runs of the job which fall within any of the windows to the end
+// of the window.
+func (p FromRRulePropertiesBuilder) Exclude(windows ...ExclusionWindow) FromRRulePropertiesBuilder {
+	p.exclusions = p.exclusions.add(windows)
+	return p
+}
+
+func (p FromRRulePropertiesBuilder) buildSkeleton() (id string, data createJobSkeleton) {
+	start := startFromRRule{
+		Type: "rrule",
+		Rule: strings.TrimPrefix(strings.TrimSpace(p.rule), "RRULE:"),
+	}
+	if !p.start.IsZero() {
+		start.DateTime = formatDatetime(p.start)
+	}
+	data = createJobSkeleton{
+		StartFrom:     start,
+		RunEvery:      nil,
+		EndpointID:    "",
+		EncryptedData: "",
+		JobType:       "",
+		Headers:       p.headers,
+		Timezone:      p.timezone,
+
+		idempotencyKey: p.idemKey,
+	}
+	p.exclusions.apply(&data)
+	p.labels.apply(&data)
+	p.conflict.apply(&data)
+	return p.id, data
+}
+
+func (p FromRRulePropertiesBuilder) validate(now time.Time, strict bool) error {
+	if _, err := parseRRule(p.rule); err != nil {
+		return err
+	}
+	if p.timezone != "" {
+		if p.timezone == "Local" {
+			return errors.New("the local timezone cannot be sent to the API, use a named location")
+		}
+		if _, err := time.LoadLocation(p.timezone); err != nil {
+			return fmt.Errorf("invalid timezone %q: %w", p.timezone, err)
+		}
+	}
+	if err := p.exclusions.validate(); err != nil {
+		return err
+	}
+	if err := p.labels.validate(); err != nil {
+		return err
+	}
+	return p.conflict.validate(p.id)
+}
@@ -0,0 +1,104 @@
+package sdk_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"go.clocktick.dev/sdk"
+)
+
+func TestRRuleExpansion(t *testing.T) {
+	start := utc2030(time.January, 1, 9, 0)
+	tests := []struct {
+		rule string
+		want []time.Time
+	}{
+		{
+			rule: "FREQ=MONTHLY;BYDAY=-1FR",
+			want: []time.Time{
+				utc2030(time.January, 25, 9, 0), utc2030(time.February, 22, 9, 0),
+				utc2030(time.March, 29, 9, 0),
+			},
+		},
+		{
+			rule: "RRULE:FREQ=MONTHLY;BYDAY=2TU;BYHOUR=14;BYMINUTE=30",
+			want: []time.Time{
+				utc2030(time.January, 8, 14, 30), utc2030(time.February, 12, 14, 30),
+				utc2030(time.March, 12, 14, 30),
+			},
+		},
+		{
+			rule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE",
+			want: []time.Time{
+				utc2030(time.January, 2, 9, 0), utc2030(time.January, 14, 9, 0),
+				utc2030(time.January, 16, 9, 0),
+			},
+		},
+		{
+			rule: "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1",
+			want: []time.Time{
+				utc2030(time.January, 31, 9, 0), utc2030(time.February, 28, 9, 0),
+				utc2030(time.March, 29, 9, 0),
+			},
+		},
+		{
+			rule: "FREQ=DAILY;COUNT=2",
+			want: []time.Time{utc2030(time.January, 1, 9, 0), utc2030(time.January, 2, 9, 0)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			assertRuns(t, sdk.PreviewRuns(sdk.FromRRule(tt.rule).Start(start), 3), tt.want...)
+		})
+	}
+}
+
+func TestRRuleKeepsWallClockInTimezone(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The clocks go forward on 31 March 2030, so 09:00 moves from 09:00 to 08:00 UTC.
+	props := sdk.FromRRule("FREQ=DAILY").Start(time.Date(2030, 3, 30, 9, 0, 0, 0, london)).
+		Timezone("Europe/London")
+	assertRuns(
+		t, sdk.PreviewRuns(props, 2),
+		utc2030(time.March, 30, 9, 0), utc2030(time.March, 31, 8, 0),
+	)
+}
+
+func TestRRuleRejectsUnsupportedRules(t *testing.T) {
+	for _, rule := range []string{
+		"", "BYDAY=MO", "FREQ=SECONDLY", "FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=DAILY;COUNT=1;UNTIL=20300101T000000Z",
+	} {
+		if runs := sdk.PreviewRuns(sdk.FromRRule(rule).Start(utc2030(time.January, 1, 0, 0)), 1); runs != nil {
+			t.Errorf("%q: got runs %v, want none", rule, runs)
+		}
+	}
+}
+
+func TestMinRecurringIntervalAppliesToRRule(t *testing.T) {
+	api := newRecordingAPI(t)
+	s, _ := newTestServer(t, api.URL, sdk.WithMinRecurringInterval(time.Hour))
+	r, _ := addRecordingRoute(s, "report", nil)
+	ctx := context.Background()
+
+	for _, rule := range []string{"FREQ=MINUTELY", "FREQ=HOURLY;BYMINUTE=0,30", "FREQ=DAILY;BYHOUR=9;BYMINUTE=0,5"} {
+		_, err := r.Schedule(ctx, sdk.FromRRule(rule), "x")
+		var validationErr *sdk.ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Errorf("%q: err = %v, want a ValidationError", rule, err)
+		}
+	}
+	if n := len(api.received()); n != 0 {
+		t.Fatalf("API received %d requests, want 0", n)
+	}
+	if _, err := r.Schedule(ctx, sdk.FromRRule("FREQ=HOURLY"), "x"); err != nil {
+		t.Errorf("hourly rule: %v", err)
+	}
+}
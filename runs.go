@@ -0,0 +1,111 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// JobRun defines a single delivery attempt of a job made by the platform.
+type JobRun struct {
+	// StartedAt is when the delivery was sent.
+	StartedAt time.Time `json:"started_at"`
+
+	// StatusCode is the HTTP status the handler responded with, or 0 if no response was
+	// received.
+	StatusCode int `json:"status_code"`
+
+	// Duration is how long the handler took to respond.
+	Duration time.Duration `json:"-"`
+
+	// Error describes why the delivery failed, if it did.
+	Error string `json:"error,omitempty"`
+}
+
+// Defines the JSON form of a run, where the duration is in milliseconds.
+type jobRunJSON struct {
+	StartedAt  time.Time `json:"started_at"`
+	StatusCode int       `json:"status_code"`
+	DurationMs int64     `json:"duration_ms"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// MarshalJSON is used to encode the run with the duration in milliseconds.
+func (r JobRun) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jobRunJSON{
+		StartedAt:  r.StartedAt,
+		StatusCode: r.StatusCode,
+		DurationMs: r.Duration.Milliseconds(),
+		Error:      r.Error,
+	})
+}
+
+// UnmarshalJSON is used to decode the run from the API.
+func (r *JobRun) UnmarshalJSON(b []byte) error {
+	var j jobRunJSON
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+	*r = JobRun{
+		StartedAt:  j.StartedAt,
+		StatusCode: j.StatusCode,
+		Duration:   time.Duration(j.DurationMs) * time.Millisecond,
+		Error:      j.Error,
+	}
+	return nil
+}
+
+// Succeeded is used to check if the handler responded with a 2xx status.
+func (r JobRun) Succeeded() bool {
+	return r.StatusCode >= 200 && r.StatusCode < 300
+}
+
+// JobRunsOptions is used to filter and page through the runs returned by JobRuns.
+type JobRunsOptions struct {
+	// Since limits the runs to those started after this time.
+	Since time.Time
+
+	// Cursor is the NextCursor of the previous page.
+	Cursor string
+
+	// Limit is the maximum number of runs in the page. If 0, the API default is used.
+	Limit int
+}
+
+// JobRunList defines a page of runs returned by JobRuns, newest first.
+type JobRunList struct {
+	Runs []JobRun `json:"runs"`
+
+	// NextCursor is the cursor of the next page, or empty if this is the last page.
+	NextCursor string `json:"next_cursor"`
+}
+
+// JobRuns is used to get the past delivery attempts of the job with the ID specified, newest
+// first, so that it can be checked whether a recurring job actually ran.
+func (c *Client) JobRuns(
+	ctx context.Context, jobId string, opts JobRunsOptions,
+) (JobRunList, error) {
+	if jobId == "" {
+		return JobRunList{}, errors.New("job ID is required")
+	}
+	query := url.Values{}
+	if !opts.Since.IsZero() {
+		query.Set("since", opts.Since.UTC().Format(time.RFC3339))
+	}
+	if opts.Cursor != "" {
+		query.Set("cursor", opts.Cursor)
+	}
+	if opts.Limit != 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	reqUrl := c.baseURL + jobsPath + "/" + url.PathEscape(jobId) + "/runs"
+	if len(query) != 0 {
+		reqUrl += "?" + query.Encode()
+	}
+	var list JobRunList
+	err := c.send(ctx, "GET", reqUrl, nil, &list)
+	return list, err
+}
@@ -0,0 +1,481 @@
+package sdktest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.clocktick.dev/sdk"
+)
+
+// API is used to fake the jobs API of the Clocktick service in process, so that code which
+// schedules and manages jobs can be tested without clocktick.dev. Pass URL to
+// sdk.WithBaseURL or sdk.WithClientBaseURL. Jobs are stored in memory and are never run.
+type API struct {
+	// URL is the base URL of the fake API.
+	URL string
+
+	server *httptest.Server
+
+	mu        sync.Mutex
+	apiKey    string
+	jobs      map[string]*sdk.Job
+	order     []string
+	nextID    int
+	idemKeys  map[string]string
+	limited   int
+	limitWait time.Duration
+	requests  int
+	runs      map[string][]sdk.JobRun
+	results   map[string]sdk.JobRunResult
+}
+
+// NewAPI is used to start a fake API. If the API key is not empty, requests with any other
+// key are rejected with a 401. Call Close when done.
+func NewAPI(apiKey string) *API {
+	a := &API{
+		apiKey:   apiKey,
+		jobs:     map[string]*sdk.Job{},
+		idemKeys: map[string]string{},
+		runs:     map[string][]sdk.JobRun{},
+		results:  map[string]sdk.JobRunResult{},
+	}
+	a.server = httptest.NewServer(a)
+	a.URL = a.server.URL
+	return a
+}
+
+// Close is used to shut down the fake API.
+func (a *API) Close() {
+	a.server.Close()
+}
+
+// RecordRun is used to add a delivery attempt to the history of the job returned by JobRuns.
+func (a *API) RecordRun(jobID string, run sdk.JobRun) {
+	a.mu.Lock()
+	a.runs[jobID] = append(a.runs[jobID], run)
+	a.mu.Unlock()
+}
+
+// Complete is used to mark the job as completed, as if it had run and the handler had returned
+// the result specified. If result is nil, the job has no result. Returns false if the job
+// does not exist.
+func (a *API) Complete(jobID string, result any) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	job, ok := a.jobs[jobID]
+	if !ok {
+		return false
+	}
+	job.Status = sdk.JobStatusCompleted
+	job.NextRunAt = nil
+	if result != nil {
+		b, err := json.Marshal(result)
+		if err != nil {
+			panic(err)
+		}
+		a.results[jobID] = sdk.JobRunResult{JobID: jobID, Result: b, CompletedAt: time.Now().UTC()}
+	}
+	return true
+}
+
+// Fail is used to mark the job as failed, as if it could not be delivered. Returns false if
+// the job does not exist.
+func (a *API) Fail(jobID string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	job, ok := a.jobs[jobID]
+	if !ok {
+		return false
+	}
+	job.Status = sdk.JobStatusFailed
+	job.NextRunAt = nil
+	return true
+}
+
+// RateLimit is used to make the next n requests fail with a 429, telling the client to retry
+// after the duration specified.
+func (a *API) RateLimit(n int, retryAfter time.Duration) {
+	a.mu.Lock()
+	a.limited = n
+	a.limitWait = retryAfter
+	a.mu.Unlock()
+}
+
+// Jobs is used to get the jobs the API currently holds, in the order they were created.
+func (a *API) Jobs() []sdk.Job {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	jobs := make([]sdk.Job, 0, len(a.order))
+	for _, id := range a.order {
+		if job, ok := a.jobs[id]; ok {
+			jobs = append(jobs, *job)
+		}
+	}
+	return jobs
+}
+
+// Job is used to get the job with the ID specified from the API.
+func (a *API) Job(id string) (sdk.Job, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	job, ok := a.jobs[id]
+	if !ok {
+		return sdk.Job{}, false
+	}
+	return *job, true
+}
+
+// Requests is used to get the number of requests the API has received, including ones which
+// were rejected.
+func (a *API) Requests() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.requests
+}
+
+// Writes the JSON response.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// Writes an application error in the format the API uses.
+func writeAPIError(w http.ResponseWriter, status int, errType string, reasons ...string) {
+	w.Header().Set("X-Is-Application-Error", "true")
+	writeJSON(w, status, sdk.APIError{Type: errType, Reasons: reasons})
+}
+
+// Defines the body of a job creation request.
+type createJobBody struct {
+	CustomID      string            `json:"custom_id"`
+	StartFrom     sdk.JobStart      `json:"start_from"`
+	RunEvery      *sdk.Delta        `json:"run_every"`
+	EndpointID    string            `json:"endpoint_id"`
+	EncryptedData string            `json:"encrypted_data"`
+	JobType       string            `json:"job_type"`
+	Until         *time.Time        `json:"until"`
+	MaxRuns       uint              `json:"max_runs"`
+	Tags          []string          `json:"tags"`
+	Metadata      map[string]string `json:"metadata"`
+	OnConflict    string            `json:"on_conflict"`
+
+	// IdempotencyKey is only set for jobs in a batch, which cannot use the header.
+	IdempotencyKey string `json:"idempotency_key"`
+}
+
+// Validates the job creation request, returning the reasons it is invalid.
+func (b *createJobBody) validate() []string {
+	var reasons []string
+	if b.JobType == "" {
+		reasons = append(reasons, "job_type is required")
+	}
+	if b.EndpointID == "" {
+		reasons = append(reasons, "endpoint_id is required")
+	}
+	if b.EncryptedData == "" {
+		reasons = append(reasons, "encrypted_data is required")
+	}
+	switch b.StartFrom.Type {
+	case "delta", "datetime":
+	case "cron":
+		if b.StartFrom.Expression == "" {
+			reasons = append(reasons, "start_from.expression is required")
+		}
+	case "rrule":
+		if b.StartFrom.Rule == "" {
+			reasons = append(reasons, "start_from.rule is required")
+		}
+	default:
+		reasons = append(reasons, "start_from.type must be delta, datetime, cron, or rrule")
+	}
+	return reasons
+}
+
+// Creates the job in the store. Must be called with the lock held.
+func (a *API) create(b *createJobBody) (string, *sdk.APIError) {
+	if reasons := b.validate(); reasons != nil {
+		return "", &sdk.APIError{Type: "validation_error", Reasons: reasons}
+	}
+	id := b.CustomID
+	replaced := false
+	if id == "" {
+		a.nextID++
+		id = "job_" + strconv.Itoa(a.nextID)
+	} else if _, ok := a.jobs[id]; ok {
+		if b.OnConflict != "replace" {
+			return "", &sdk.APIError{Type: "conflict", Reasons: []string{"a job with this ID already exists"}}
+		}
+		replaced = true
+	}
+	a.jobs[id] = &sdk.Job{
+		ID:            id,
+		CustomID:      b.CustomID,
+		Route:         b.JobType,
+		EndpointID:    b.EndpointID,
+		Status:        sdk.JobStatusScheduled,
+		StartFrom:     b.StartFrom,
+		RunEvery:      b.RunEvery,
+		CreatedAt:     time.Now().UTC(),
+		EncryptedData: b.EncryptedData,
+		Until:         b.Until,
+		MaxRuns:       b.MaxRuns,
+		Tags:          b.Tags,
+		Metadata:      b.Metadata,
+	}
+	if !replaced {
+		a.order = append(a.order, id)
+	}
+	return id, nil
+}
+
+// ServeHTTP is used to handle a request to the fake API.
+func (a *API) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.requests++
+
+	// Handle the rate limit and authentication.
+	if a.limited > 0 {
+		a.limited--
+		w.Header().Set("Retry-After", strconv.Itoa(int(a.limitWait.Seconds())))
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+		return
+	}
+	if a.apiKey != "" && r.Header.Get("Authorization") != "Bearer "+a.apiKey {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	path := strings.Trim(r.URL.Path, "/")
+	switch {
+	case path == "batch/jobs" && r.Method == "POST":
+		a.batch(w, r)
+	case path == "jobs" && r.Method == "POST":
+		a.createJob(w, r, "")
+	case path == "jobs" && r.Method == "GET":
+		a.list(w, r)
+	case strings.HasPrefix(path, "jobs/"):
+		parts := strings.Split(strings.TrimPrefix(path, "jobs/"), "/")
+		a.job(w, r, parts)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// Handles the creation of a single job.
+func (a *API) createJob(w http.ResponseWriter, r *http.Request, customID string) {
+	var body createJobBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "validation_error", "body is not valid JSON")
+		return
+	}
+	body.CustomID = customID
+
+	// Return the same job for a repeated idempotency key.
+	key := r.Header.Get("Idempotency-Key")
+	if id, ok := a.idemKeys[key]; ok && key != "" {
+		writeJSON(w, http.StatusOK, sdk.JobCreationResponse{JobID: id})
+		return
+	}
+
+	id, apiErr := a.create(&body)
+	if apiErr != nil {
+		status := http.StatusBadRequest
+		if apiErr.Type == "conflict" {
+			status = http.StatusConflict
+		}
+		writeAPIError(w, status, apiErr.Type, apiErr.Reasons...)
+		return
+	}
+	if key != "" {
+		a.idemKeys[key] = id
+	}
+	writeJSON(w, http.StatusOK, sdk.JobCreationResponse{JobID: id})
+}
+
+// Defines the result of a job in a batch.
+type batchResult struct {
+	JobID string        `json:"job_id,omitempty"`
+	Error *sdk.APIError `json:"error"`
+}
+
+// Handles the creation of a batch of jobs.
+func (a *API) batch(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Jobs []createJobBody `json:"jobs"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "validation_error", "body is not valid JSON")
+		return
+	}
+	results := make([]batchResult, len(body.Jobs))
+	for i := range body.Jobs {
+		// Return the same job for a repeated idempotency key.
+		key := body.Jobs[i].IdempotencyKey
+		if id, ok := a.idemKeys[key]; ok && key != "" {
+			results[i].JobID = id
+			continue
+		}
+		results[i].JobID, results[i].Error = a.create(&body.Jobs[i])
+		if key != "" && results[i].Error == nil {
+			a.idemKeys[key] = results[i].JobID
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"results": results})
+}
+
+// Handles listing the jobs with the filters and cursor of the query.
+func (a *API) list(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var createdAfter time.Time
+	if s := query.Get("created_after"); s != "" {
+		var err error
+		if createdAfter, err = time.Parse(time.RFC3339, s); err != nil {
+			writeAPIError(w, http.StatusBadRequest, "validation_error", "created_after is not a valid time")
+			return
+		}
+	}
+	start, _ := strconv.Atoi(query.Get("cursor"))
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	if limit < 1 {
+		limit = 100
+	}
+
+	list := sdk.JobList{Jobs: []sdk.Job{}}
+	for i := start; i < len(a.order); i++ {
+		job, ok := a.jobs[a.order[i]]
+		switch {
+		case !ok:
+		case query.Get("route") != "" && job.Route != query.Get("route"):
+		case query.Get("endpoint_id") != "" && job.EndpointID != query.Get("endpoint_id"):
+		case query.Get("status") != "" && string(job.Status) != query.Get("status"):
+		case query.Get("tag") != "" && !hasTag(job, query.Get("tag")):
+		case query.Get("custom_id") != "" && job.CustomID != query.Get("custom_id"):
+		case !createdAfter.IsZero() && !job.CreatedAt.After(createdAfter):
+		default:
+			if len(list.Jobs) == limit {
+				list.NextCursor = strconv.Itoa(i)
+				writeJSON(w, http.StatusOK, list)
+				return
+			}
+			list.Jobs = append(list.Jobs, *job)
+		}
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+// Handles listing the runs of a job, newest first.
+func (a *API) listRuns(w http.ResponseWriter, r *http.Request, id string) {
+	query := r.URL.Query()
+	var since time.Time
+	if s := query.Get("since"); s != "" {
+		var err error
+		if since, err = time.Parse(time.RFC3339, s); err != nil {
+			writeAPIError(w, http.StatusBadRequest, "validation_error", "since is not a valid time")
+			return
+		}
+	}
+	start, _ := strconv.Atoi(query.Get("cursor"))
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	if limit < 1 {
+		limit = 100
+	}
+
+	runs := a.runs[id]
+	list := sdk.JobRunList{Runs: []sdk.JobRun{}}
+	for i := start; i < len(runs); i++ {
+		run := runs[len(runs)-1-i]
+		if !since.IsZero() && !run.StartedAt.After(since) {
+			continue
+		}
+		if len(list.Runs) == limit {
+			list.NextCursor = strconv.Itoa(i)
+			break
+		}
+		list.Runs = append(list.Runs, run)
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+// Handles the requests for a single job.
+func (a *API) job(w http.ResponseWriter, r *http.Request, parts []string) {
+	id := parts[0]
+	if len(parts) == 1 && r.Method == "POST" {
+		a.createJob(w, r, id)
+		return
+	}
+	job, ok := a.jobs[id]
+	if !ok {
+		writeAPIError(w, http.StatusNotFound, "not_found", "job not found")
+		return
+	}
+
+	switch {
+	case len(parts) == 1 && r.Method == "GET":
+		writeJSON(w, http.StatusOK, job)
+	case len(parts) == 1 && r.Method == "DELETE":
+		delete(a.jobs, id)
+		delete(a.runs, id)
+		delete(a.results, id)
+		w.WriteHeader(http.StatusNoContent)
+	case len(parts) == 1 && r.Method == "PATCH":
+		var body createJobBody
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeAPIError(w, http.StatusBadRequest, "validation_error", "body is not valid JSON")
+			return
+		}
+		job.StartFrom = body.StartFrom
+		job.RunEvery = body.RunEvery
+		job.Until = body.Until
+		job.MaxRuns = body.MaxRuns
+		if body.EncryptedData != "" {
+			job.EncryptedData = body.EncryptedData
+		}
+		if body.Tags != nil {
+			job.Tags = body.Tags
+		}
+		if body.Metadata != nil {
+			job.Metadata = body.Metadata
+		}
+		writeJSON(w, http.StatusOK, job)
+	case len(parts) == 2 && parts[1] == "result" && r.Method == "GET":
+		result, ok := a.results[id]
+		if !ok {
+			writeAPIError(w, http.StatusNotFound, "not_found", "job has no result")
+			return
+		}
+		writeJSON(w, http.StatusOK, result)
+	case len(parts) == 2 && parts[1] == "runs" && r.Method == "GET":
+		a.listRuns(w, r, id)
+	case len(parts) == 2 && parts[1] == "pause" && r.Method == "POST":
+		job.Status = sdk.JobStatusPaused
+		w.WriteHeader(http.StatusNoContent)
+	case len(parts) == 2 && parts[1] == "resume" && r.Method == "POST":
+		job.Status = sdk.JobStatusScheduled
+		w.WriteHeader(http.StatusNoContent)
+	case len(parts) == 2 && parts[1] == "heartbeat" && r.Method == "POST":
+		w.WriteHeader(http.StatusNoContent)
+	case len(parts) == 2 && parts[1] == "trigger" && r.Method == "POST":
+		// Jobs are never run, so there is nothing to do.
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// Checks if the job has the tag.
+func hasTag(job *sdk.Job, tag string) bool {
+	for _, t := range job.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,206 @@
+package sdktest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"go.clocktick.dev/sdk"
+	"go.clocktick.dev/sdk/sdktest"
+)
+
+// Starts a fake API which is closed when the test ends, and a client for it.
+func newAPIClient(t *testing.T, opts ...sdk.ClientOption) (*sdktest.API, *sdk.Client) {
+	t.Helper()
+	api := sdktest.NewAPI("api-key")
+	t.Cleanup(api.Close)
+	opts = append([]sdk.ClientOption{
+		sdk.WithClientBaseURL(api.URL),
+		sdk.WithClientEncryptionKey("test-encryption-key"),
+		sdk.WithClientEndpointID("endpoint"),
+	}, opts...)
+	return api, sdk.NewClient("api-key", opts...)
+}
+
+// Schedules a job for the route, failing the test if it cannot be.
+func schedule(t *testing.T, c *sdk.Client, route string, props sdk.ScheduleJobPropertiesBuilder) string {
+	t.Helper()
+	res, err := c.ScheduleJob(context.Background(), route, props, "ada")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res.JobID
+}
+
+func TestAPIRejectsWrongKey(t *testing.T) {
+	api, _ := newAPIClient(t)
+	c := sdk.NewClient(
+		"wrong-key", sdk.WithClientBaseURL(api.URL),
+		sdk.WithClientEncryptionKey("test-encryption-key"), sdk.WithClientEndpointID("endpoint"),
+	)
+	_, err := c.ScheduleJob(context.Background(), "greet", sdk.FromNow().Minutes(1), "ada")
+	var authErr sdk.AuthError
+	if !errors.As(err, &authErr) || authErr.Status != http.StatusUnauthorized {
+		t.Errorf("err = %v, want an AuthError for a 401", err)
+	}
+	if n := len(api.Jobs()); n != 0 {
+		t.Errorf("API has %d jobs, want 0", n)
+	}
+}
+
+func TestAPIStoresJobs(t *testing.T) {
+	api, c := newAPIClient(t)
+	ctx := context.Background()
+	id := schedule(t, c, "greet", sdk.FromNow().Minutes(1))
+
+	job, err := c.GetJob(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Route != "greet" || job.EndpointID != "endpoint" || job.Status != sdk.JobStatusScheduled {
+		t.Errorf("got job %+v", job)
+	}
+
+	if err := c.PauseJob(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	if job, _ := api.Job(id); job.Status != sdk.JobStatusPaused {
+		t.Errorf("status after pause = %v", job.Status)
+	}
+	if err := c.ResumeJob(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	if job, _ := api.Job(id); job.Status != sdk.JobStatusScheduled {
+		t.Errorf("status after resume = %v", job.Status)
+	}
+
+	if err := c.DeleteJob(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := api.Job(id); ok {
+		t.Error("job was not deleted")
+	}
+	if err := c.DeleteJob(ctx, id); err == nil {
+		t.Error("deleting a missing job returned no error")
+	}
+}
+
+func TestAPIRejectsDuplicateCustomIDs(t *testing.T) {
+	api, c := newAPIClient(t)
+	schedule(t, c, "greet", sdk.FromNow().Minutes(1).CustomID("daily"))
+
+	_, err := c.ScheduleJob(context.Background(), "greet", sdk.FromNow().Minutes(1).CustomID("daily"), "ada")
+	if !errors.Is(err, sdk.ErrJobExists) {
+		t.Errorf("err = %v, want ErrJobExists", err)
+	}
+	job, err := c.GetJobByCustomID(context.Background(), "daily")
+	if err != nil || job.CustomID != "daily" {
+		t.Errorf("got job %+v, err %v", job, err)
+	}
+	if n := len(api.Jobs()); n != 1 {
+		t.Errorf("API has %d jobs, want 1", n)
+	}
+}
+
+func TestAPIDeduplicatesIdempotencyKeys(t *testing.T) {
+	api, c := newAPIClient(t)
+	first := schedule(t, c, "greet", sdk.FromNow().Minutes(1).IdempotencyKey("k1"))
+	second := schedule(t, c, "greet", sdk.FromNow().Minutes(1).IdempotencyKey("k1"))
+	if first != second {
+		t.Errorf("got jobs %q and %q, want the same job", first, second)
+	}
+	if n := len(api.Jobs()); n != 1 {
+		t.Errorf("API has %d jobs, want 1", n)
+	}
+}
+
+func TestAPIRateLimits(t *testing.T) {
+	api, c := newAPIClient(t, sdk.WithClientRetryPolicy(sdk.RetryPolicy{
+		MaxAttempts: 3, MaxRateLimitWait: 5 * time.Second,
+	}))
+	api.RateLimit(1, time.Second)
+
+	start := time.Now()
+	schedule(t, c, "greet", sdk.FromNow().Minutes(1).IdempotencyKey("k1"))
+	if waited := time.Since(start); waited < time.Second {
+		t.Errorf("retried after %v, want the Retry-After of 1s", waited)
+	}
+	if n := api.Requests(); n != 2 {
+		t.Errorf("API received %d requests, want 2", n)
+	}
+}
+
+func TestAPIListsJobs(t *testing.T) {
+	_, c := newAPIClient(t)
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		schedule(t, c, "greet", sdk.FromNow().Minutes(1))
+	}
+	schedule(t, c, "email", sdk.FromNow().Minutes(1))
+
+	var ids []string
+	opts := sdk.ListJobsOptions{Route: "greet", Limit: 2}
+	for {
+		list, err := c.ListJobs(ctx, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, job := range list.Jobs {
+			ids = append(ids, job.ID)
+		}
+		if list.NextCursor == "" {
+			break
+		}
+		opts.Cursor = list.NextCursor
+	}
+	if len(ids) != 3 {
+		t.Errorf("listed %v, want the 3 greet jobs", ids)
+	}
+}
+
+func TestAPIStoresLabels(t *testing.T) {
+	_, c := newAPIClient(t)
+	ctx := context.Background()
+	id := schedule(t, c, "greet", sdk.FromNow().Minutes(1).Tag("billing").Metadata("team", "payments"))
+	schedule(t, c, "greet", sdk.FromNow().Minutes(1).Tag("email"))
+
+	job, err := c.GetJob(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(job.Tags) != 1 || job.Tags[0] != "billing" || job.Metadata["team"] != "payments" {
+		t.Errorf("tags = %v, metadata = %v", job.Tags, job.Metadata)
+	}
+	list, err := c.ListJobs(ctx, sdk.ListJobsOptions{Tag: "billing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Jobs) != 1 || list.Jobs[0].ID != id {
+		t.Errorf("listed %+v, want only the billing job", list.Jobs)
+	}
+}
+
+func TestAPICompletesJobs(t *testing.T) {
+	api, c := newAPIClient(t)
+	done := schedule(t, c, "greet", sdk.FromNow().Minutes(1))
+	failed := schedule(t, c, "greet", sdk.FromNow().Minutes(1))
+
+	if !api.Complete(done, map[string]int{"sent": 3}) || !api.Fail(failed) {
+		t.Fatal("jobs were not found")
+	}
+	result, err := c.GetJobResult(context.Background(), done)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(result.Result) != `{"sent":3}` {
+		t.Errorf("result = %s", result.Result)
+	}
+	if job, _ := api.Job(failed); job.Status != sdk.JobStatusFailed || job.NextRunAt != nil {
+		t.Errorf("failed job = %+v", job)
+	}
+	if api.Complete("missing", nil) {
+		t.Error("completed a missing job")
+	}
+}
@@ -0,0 +1,146 @@
+package sdktest
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"go.clocktick.dev/sdk"
+)
+
+// GenerateKeys is used to generate an Ed25519 keypair for tests. The public key is hex
+// encoded so it can be passed straight to sdk.NewServer.
+func GenerateKeys() (publicKey string, privateKey ed25519.PrivateKey, err error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", nil, err
+	}
+	return hex.EncodeToString(pub), priv, nil
+}
+
+// SignerOption is used to define an option for a Signer.
+type SignerOption func(*Signer)
+
+// WithCipher is used to set the cipher deliveries are encrypted with. Defaults to AES-GCM.
+func WithCipher(c sdk.Cipher) SignerOption {
+	return func(s *Signer) {
+		s.cipher = c
+	}
+}
+
+// WithEncryptor is used to encrypt deliveries with the encryptor instead of the encryption
+// key. Use this when the server was created with sdk.WithEncryptor.
+func WithEncryptor(e sdk.Encryptor) SignerOption {
+	return func(s *Signer) {
+		s.encryptor = e
+	}
+}
+
+// WithHMACSecret is used to sign deliveries with HMAC-SHA256 and the shared secret instead of
+// the private key. Use this when the server was created with sdk.WithHMACSecret, in which case
+// the private key passed to NewSigner can be nil.
+func WithHMACSecret(secret string) SignerOption {
+	return func(s *Signer) {
+		s.hmacSecret = []byte(secret)
+	}
+}
+
+// Signer is used to build signed and encrypted deliveries in the same way the Clocktick
+// service does, so handlers can be tested through ServeHTTP.
+type Signer struct {
+	privateKey ed25519.PrivateKey
+	hmacSecret []byte
+	cipher     sdk.Cipher
+	encryptor  sdk.Encryptor
+}
+
+// NewSigner is used to create a signer from the private key matching the public key the
+// server was created with and the encryption key the server was created with. Returns an
+// error if the private key is not valid and WithHMACSecret is not used.
+func NewSigner(privateKey ed25519.PrivateKey, encryptionKey string, opts ...SignerOption) (*Signer, error) {
+	s := &Signer{privateKey: privateKey}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.hmacSecret == nil && len(privateKey) != ed25519.PrivateKeySize {
+		return nil, errors.New("private key is required without an HMAC secret")
+	}
+	if s.encryptor == nil {
+		// Use the encryption of the SDK so that deliveries always match what it expects.
+		e, err := sdk.NewKeyEncryptor(encryptionKey, s.cipher)
+		if err != nil {
+			return nil, err
+		}
+		s.encryptor = e
+	}
+	return s, nil
+}
+
+// Encrypts the arguments into the encrypted_data format.
+func (s *Signer) encrypt(args []any) (string, error) {
+	if args == nil {
+		args = []any{}
+	}
+	data, err := msgpack.Marshal(args)
+	if err != nil {
+		return "", err
+	}
+	return s.encryptor.Encrypt(context.Background(), data)
+}
+
+// Body is used to build the JSON body of a delivery of the job to the route with the
+// arguments specified.
+func (s *Signer) Body(route, jobID string, args ...any) ([]byte, error) {
+	encryptedData, err := s.encrypt(args)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(map[string]string{
+		"type":           route,
+		"job_id":         jobID,
+		"encrypted_data": encryptedData,
+	})
+}
+
+// Sign is used to build a signed POST request to the URL for the body, timestamped at the
+// time specified. The body does not have to come from Body, which is useful for testing
+// how malformed deliveries are handled.
+func (s *Signer) Sign(url string, body []byte, ts time.Time) (*http.Request, error) {
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	tsHeader := strconv.FormatInt(ts.Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Signature-Timestamp", tsHeader)
+	if s.hmacSecret != nil {
+		mac := hmac.New(sha256.New, s.hmacSecret)
+		mac.Write([]byte(tsHeader))
+		mac.Write(body)
+		req.Header.Set(sdk.HMACSignatureHeader, hex.EncodeToString(mac.Sum(nil)))
+		return req, nil
+	}
+	sig := ed25519.Sign(s.privateKey, append([]byte(tsHeader), body...))
+	req.Header.Set("X-Signature-Ed25519", hex.EncodeToString(sig))
+	return req, nil
+}
+
+// Request is used to build a signed and encrypted delivery of the job to the route, ready to
+// be passed to ServeHTTP. The request is timestamped now.
+func (s *Signer) Request(route, jobID string, args ...any) (*http.Request, error) {
+	body, err := s.Body(route, jobID, args...)
+	if err != nil {
+		return nil, err
+	}
+	return s.Sign("/", body, time.Now())
+}
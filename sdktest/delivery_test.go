@@ -0,0 +1,121 @@
+package sdktest_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"go.clocktick.dev/sdk"
+	"go.clocktick.dev/sdk/sdktest"
+)
+
+const encryptionKey = "test-encryption-key"
+
+// Creates a server for the public key with a route which records the names it is called with.
+func newServer(t *testing.T, publicKey string, opts ...sdk.ServerOption) (*sdk.Server, *[]string) {
+	t.Helper()
+	s, err := sdk.NewServerE("", encryptionKey, publicKey, "endpoint", opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	sdk.AddRoute1(s, "greet", func(_ context.Context, name string) error {
+		names = append(names, name)
+		return nil
+	})
+	return s, &names
+}
+
+func TestSignerDeliveriesAreAccepted(t *testing.T) {
+	for name, c := range map[string]sdk.Cipher{
+		"aes-gcm":           sdk.CipherAESGCM,
+		"xchacha20poly1305": sdk.CipherXChaCha20Poly1305,
+	} {
+		t.Run(name, func(t *testing.T) {
+			pub, priv, err := sdktest.GenerateKeys()
+			if err != nil {
+				t.Fatal(err)
+			}
+			s, names := newServer(t, pub)
+			signer, err := sdktest.NewSigner(priv, encryptionKey, sdktest.WithCipher(c))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req, err := signer.Request("greet", "job_1", "ada")
+			if err != nil {
+				t.Fatal(err)
+			}
+			w := httptest.NewRecorder()
+			s.ServeHTTP(w, req)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200: %s", w.Code, w.Body)
+			}
+			if len(*names) != 1 || (*names)[0] != "ada" {
+				t.Errorf("handler got %v, want [ada]", *names)
+			}
+		})
+	}
+}
+
+func TestSignerUsesSDKEncryption(t *testing.T) {
+	_, priv, err := sdktest.GenerateKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := sdktest.NewSigner(priv, encryptionKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := signer.Body("greet", "job_1", "ada", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var delivery map[string]string
+	if err := json.Unmarshal(body, &delivery); err != nil {
+		t.Fatal(err)
+	}
+	if delivery["type"] != "greet" || delivery["job_id"] != "job_1" {
+		t.Errorf("got delivery %v", delivery)
+	}
+
+	e, err := sdk.NewKeyEncryptor(encryptionKey, sdk.CipherAESGCM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, err := e.Decrypt(context.Background(), delivery["encrypted_data"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var args []any
+	if err := msgpack.Unmarshal(plain, &args); err != nil {
+		t.Fatal(err)
+	}
+	if len(args) != 2 || args[0] != "ada" {
+		t.Errorf("args = %v, want [ada 2]", args)
+	}
+}
+
+func TestNewSignerRequiresKey(t *testing.T) {
+	if _, err := sdktest.NewSigner(nil, encryptionKey); err == nil {
+		t.Error("NewSigner with no private key or HMAC secret returned no error")
+	}
+	signer, err := sdktest.NewSigner(nil, encryptionKey, sdktest.WithHMACSecret("secret"))
+	if err != nil {
+		t.Fatalf("NewSigner with an HMAC secret: %v", err)
+	}
+
+	// The HMAC signed delivery is accepted by a server with the same secret.
+	s, names := newServer(t, "", sdk.WithHMACSecret("secret"))
+	req, err := signer.Request("greet", "job_1", "grace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || len(*names) != 1 {
+		t.Errorf("status = %d, calls = %v", w.Code, *names)
+	}
+}
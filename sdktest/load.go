@@ -0,0 +1,186 @@
+// Package sdktest is used to test code built on the Clocktick SDK without the Clocktick
+// service.
+package sdktest
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// LoadGenerator is used to send signed and encrypted deliveries to a handler at a given rate
+// and concurrency, measuring how long each takes. This is useful for capacity planning
+// endpoints without the Clocktick service.
+type LoadGenerator struct {
+	// Handler is the handler deliveries are sent to in process, usually a *sdk.Server.
+	// If nil, deliveries are sent over HTTP to URL instead.
+	Handler http.Handler
+
+	// URL is the URL deliveries are sent to when Handler is nil.
+	URL string
+
+	// Client is the client used when sending to URL. If nil, http.DefaultClient is used.
+	Client *http.Client
+
+	// PrivateKey is the private key matching the public key the server was created with.
+	PrivateKey ed25519.PrivateKey
+
+	// EncryptionKey is the encryption key the server was created with.
+	EncryptionKey string
+
+	// Route is the route the deliveries are for and Args are the arguments they carry.
+	Route string
+	Args  []any
+
+	// Requests is the number of deliveries to send.
+	Requests int
+
+	// Concurrency is the number of deliveries in flight at once. Defaults to 1.
+	Concurrency int
+
+	// Rate is the maximum number of deliveries started per second. If 0, there is no limit.
+	Rate float64
+}
+
+// LoadReport defines the results of a load generation run.
+type LoadReport struct {
+	Requests    int
+	Failures    int
+	StatusCodes map[int]int
+	Duration    time.Duration
+
+	P50 time.Duration
+	P90 time.Duration
+	P99 time.Duration
+	Max time.Duration
+}
+
+// Gets the percentile from the sorted latencies.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := int(float64(len(sorted)-1) * p)
+	return sorted[i]
+}
+
+// Sends a single delivery, returning the status code.
+func (g *LoadGenerator) send(ctx context.Context, b *Signer, n int) (int, error) {
+	body, err := b.Body(g.Route, "load-"+strconv.Itoa(n), g.Args...)
+	if err != nil {
+		return 0, err
+	}
+	url := g.URL
+	if g.Handler != nil {
+		url = "/"
+	}
+	req, err := b.Sign(url, body, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	req = req.WithContext(ctx)
+
+	if g.Handler != nil {
+		rec := httptest.NewRecorder()
+		g.Handler.ServeHTTP(rec, req)
+		return rec.Code, nil
+	}
+	client := g.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	_ = resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// Run is used to run the load generator until all of the requests have been sent or the
+// context is cancelled. Deliveries which error or return a non-2xx status are failures.
+func (g *LoadGenerator) Run(ctx context.Context) (LoadReport, error) {
+	if g.Handler == nil && g.URL == "" {
+		return LoadReport{}, errors.New("either Handler or URL must be set")
+	}
+	b, err := NewSigner(g.PrivateKey, g.EncryptionKey)
+	if err != nil {
+		return LoadReport{}, err
+	}
+	concurrency := g.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	// Start the ticker which limits the rate.
+	var tick <-chan time.Time
+	if g.Rate > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / g.Rate))
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	report := LoadReport{StatusCodes: map[int]int{}}
+	latencies := make([]time.Duration, 0, g.Requests)
+	var mu sync.Mutex
+	work := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for w := 0; w < concurrency; w++ {
+		go func() {
+			defer wg.Done()
+			for n := range work {
+				start := time.Now()
+				status, err := g.send(ctx, b, n)
+				latency := time.Since(start)
+
+				mu.Lock()
+				report.Requests++
+				latencies = append(latencies, latency)
+				if err != nil || status < 200 || status > 299 {
+					report.Failures++
+				}
+				if err == nil {
+					report.StatusCodes[status]++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	start := time.Now()
+loop:
+	for n := 0; n < g.Requests; n++ {
+		if tick != nil {
+			select {
+			case <-tick:
+			case <-ctx.Done():
+				break loop
+			}
+		}
+		select {
+		case work <- n:
+		case <-ctx.Done():
+			break loop
+		}
+	}
+	close(work)
+	wg.Wait()
+	report.Duration = time.Since(start)
+
+	// Work out the percentiles.
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	report.P50 = percentile(latencies, 0.5)
+	report.P90 = percentile(latencies, 0.9)
+	report.P99 = percentile(latencies, 0.99)
+	if len(latencies) != 0 {
+		report.Max = latencies[len(latencies)-1]
+	}
+	return report, ctx.Err()
+}
@@ -0,0 +1,36 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const simulatePath = "/simulate"
+
+type simulateRequest struct {
+	createJobSkeleton
+	Count int `json:"count"`
+}
+
+type simulateResponse struct {
+	Runs []time.Time `json:"runs"`
+}
+
+// SimulateSchedule is used to ask the API when a job with the properties specified would run
+// without creating it. Up to n run times are returned, computed by the API with the same
+// calendar and DST rules it uses when running jobs.
+func (c *Client) SimulateSchedule(
+	ctx context.Context, props ScheduleJobPropertiesBuilder, n int,
+) ([]time.Time, error) {
+	if n < 1 {
+		return nil, errors.New("n must be at least 1")
+	}
+	_, body := props.buildSkeleton()
+	var respBody simulateResponse
+	err := c.send(
+		ctx, "POST", c.baseURL+simulatePath,
+		simulateRequest{createJobSkeleton: body, Count: n}, &respBody,
+	)
+	return respBody.Runs, err
+}
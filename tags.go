@@ -0,0 +1,103 @@
+package sdk
+
+import "errors"
+
+// Defines the tags and metadata of a job.
+type labels struct {
+	tags     []string
+	metadata map[string]string
+}
+
+// Adds the tags without changing the ones builders may share.
+func (l labels) withTags(tags []string) labels {
+	l.tags = append(l.tags[:len(l.tags):len(l.tags)], tags...)
+	return l
+}
+
+// Sets the metadata key without changing the map builders may share.
+func (l labels) withMetadata(key, value string) labels {
+	m := make(map[string]string, len(l.metadata)+1)
+	for k, v := range l.metadata {
+		m[k] = v
+	}
+	m[key] = value
+	l.metadata = m
+	return l
+}
+
+// Checks the tags and metadata keys are not empty.
+func (l labels) validate() error {
+	for _, t := range l.tags {
+		if t == "" {
+			return errors.New("tags must not be empty")
+		}
+	}
+	for k := range l.metadata {
+		if k == "" {
+			return errors.New("metadata keys must not be empty")
+		}
+	}
+	return nil
+}
+
+// Adds the tags and metadata to the skeleton.
+func (l labels) apply(data *createJobSkeleton) {
+	data.Tags = l.tags
+	data.Metadata = l.metadata
+}
+
+// Tag is used to attach tags to the job, such as "customer:123". Tags are returned by
+// ListJobs and GetJob and can be filtered on, so that jobs can be grouped and managed together.
+func (p FromNowPropertiesBuilder) Tag(tags ...string) FromNowPropertiesBuilder {
+	p.labels = p.labels.withTags(tags)
+	return p
+}
+
+// Metadata is used to attach a key-value pair to the job. Unlike headers, metadata is not
+// sent on delivery, but is returned by ListJobs and GetJob.
+func (p FromNowPropertiesBuilder) Metadata(key, value string) FromNowPropertiesBuilder {
+	p.labels = p.labels.withMetadata(key, value)
+	return p
+}
+
+// Tag is used to attach tags to the job, such as "customer:123". Tags are returned by
+// ListJobs and GetJob and can be filtered on, so that jobs can be grouped and managed together.
+func (p FromTimePropertiesBuilder) Tag(tags ...string) FromTimePropertiesBuilder {
+	p.labels = p.labels.withTags(tags)
+	return p
+}
+
+// Metadata is used to attach a key-value pair to the job. Unlike headers, metadata is not
+// sent on delivery, but is returned by ListJobs and GetJob.
+func (p FromTimePropertiesBuilder) Metadata(key, value string) FromTimePropertiesBuilder {
+	p.labels = p.labels.withMetadata(key, value)
+	return p
+}
+
+// Tag is used to attach tags to the job, such as "customer:123". Tags are returned by
+// ListJobs and GetJob and can be filtered on, so that jobs can be grouped and managed together.
+func (p FromCronPropertiesBuilder) Tag(tags ...string) FromCronPropertiesBuilder {
+	p.labels = p.labels.withTags(tags)
+	return p
+}
+
+// Metadata is used to attach a key-value pair to the job. Unlike headers, metadata is not
+// sent on delivery, but is returned by ListJobs and GetJob.
+func (p FromCronPropertiesBuilder) Metadata(key, value string) FromCronPropertiesBuilder {
+	p.labels = p.labels.withMetadata(key, value)
+	return p
+}
+
+// Tag is used to attach tags to the job, such as "customer:123". Tags are returned by
+// ListJobs and GetJob and can be filtered on, so that jobs can be grouped and managed together.
+func (p FromRRulePropertiesBuilder) Tag(tags ...string) FromRRulePropertiesBuilder {
+	p.labels = p.labels.withTags(tags)
+	return p
+}
+
+// Metadata is used to attach a key-value pair to the job. Unlike headers, metadata is not
+// sent on delivery, but is returned by ListJobs and GetJob.
+func (p FromRRulePropertiesBuilder) Metadata(key, value string) FromRRulePropertiesBuilder {
+	p.labels = p.labels.withMetadata(key, value)
+	return p
+}
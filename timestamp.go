@@ -0,0 +1,44 @@
+package sdk
+
+import "time"
+
+// The default timestamp window of deliveries.
+const (
+	defaultTimestampMaxAge  = 5 * time.Minute
+	defaultTimestampMaxSkew = 5 * time.Minute
+)
+
+// WithTimestampWindow is used to set how old the timestamp of a delivery can be and how far
+// in the future it can be before the delivery is rejected. Loosen this for deployments with
+// poor clock sync, or tighten it to shorten the time a captured request is valid for. Both
+// default to 5 minutes, and zero or negative values use the default.
+func WithTimestampWindow(maxAge, maxSkew time.Duration) ServerOption {
+	return func(s *Server) {
+		s.timestampMaxAge = maxAge
+		s.timestampMaxSkew = maxSkew
+	}
+}
+
+// Gets the timestamp window with the defaults applied.
+func (s *Server) timestampWindow() (maxAge, maxSkew time.Duration) {
+	maxAge, maxSkew = s.timestampMaxAge, s.timestampMaxSkew
+	if maxAge <= 0 {
+		maxAge = defaultTimestampMaxAge
+	}
+	if maxSkew <= 0 {
+		maxSkew = defaultTimestampMaxSkew
+	}
+	return
+}
+
+// Checks the timestamp of the delivery is within the window, returning the reason if not.
+func (s *Server) checkTimestamp(ts, now time.Time) string {
+	maxAge, maxSkew := s.timestampWindow()
+	if now.Sub(ts) > maxAge {
+		return "timestamp is outdated"
+	}
+	if ts.Sub(now) > maxSkew {
+		return "timestamp is in the future"
+	}
+	return ""
+}
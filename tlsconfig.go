@@ -0,0 +1,97 @@
+package sdk
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+)
+
+// Copies the HTTP client with its transport changed by f. The client is returned as is if it
+// does not use a *http.Transport, so a custom transport is never replaced.
+func withTransport(client *http.Client, f func(t *http.Transport)) *http.Client {
+	t, ok := client.Transport.(*http.Transport)
+	if !ok {
+		return client
+	}
+	t = t.Clone()
+	f(t)
+	c := *client
+	c.Transport = t
+	return &c
+}
+
+// Copies the HTTP client with the TLS config of its transport changed by f.
+func withTLSConfig(client *http.Client, f func(cfg *tls.Config)) *http.Client {
+	return withTransport(client, func(t *http.Transport) {
+		if t.TLSClientConfig == nil {
+			t.TLSClientConfig = &tls.Config{}
+		}
+		f(t.TLSClientConfig)
+	})
+}
+
+// WithTLSCertificates is used to present the certificates specified to the API, for networks
+// which require mutual TLS. Like WithDialContext, this only applies when the HTTP client uses
+// a *http.Transport and is replaced by a later call to SetClient.
+func WithTLSCertificates(certs ...tls.Certificate) ServerOption {
+	return func(s *Server) {
+		s.client = withTLSConfig(s.client, func(cfg *tls.Config) {
+			cfg.Certificates = append(cfg.Certificates, certs...)
+		})
+	}
+}
+
+// WithClientTLSCertificates is used to present the certificates specified to the API, for
+// networks which require mutual TLS. This only applies when the HTTP client uses a
+// *http.Transport.
+func WithClientTLSCertificates(certs ...tls.Certificate) ClientOption {
+	return func(c *Client) {
+		c.client = withTLSConfig(c.client, func(cfg *tls.Config) {
+			cfg.Certificates = append(cfg.Certificates, certs...)
+		})
+	}
+}
+
+// WithRootCAs is used to set the certificate authorities the certificate of the API is
+// verified against, such as the CA of a TLS intercepting proxy. Like WithDialContext, this
+// only applies when the HTTP client uses a *http.Transport and is replaced by a later call to
+// SetClient.
+func WithRootCAs(pool *x509.CertPool) ServerOption {
+	return func(s *Server) {
+		s.client = withTLSConfig(s.client, func(cfg *tls.Config) {
+			cfg.RootCAs = pool
+		})
+	}
+}
+
+// WithClientRootCAs is used to set the certificate authorities the certificate of the API is
+// verified against, such as the CA of a TLS intercepting proxy. This only applies when the
+// HTTP client uses a *http.Transport.
+func WithClientRootCAs(pool *x509.CertPool) ClientOption {
+	return func(c *Client) {
+		c.client = withTLSConfig(c.client, func(cfg *tls.Config) {
+			cfg.RootCAs = pool
+		})
+	}
+}
+
+// WithMinTLSVersion is used to set the minimum TLS version used to talk to the API, such as
+// tls.VersionTLS13. Like WithDialContext, this only applies when the HTTP client uses a
+// *http.Transport and is replaced by a later call to SetClient.
+func WithMinTLSVersion(version uint16) ServerOption {
+	return func(s *Server) {
+		s.client = withTLSConfig(s.client, func(cfg *tls.Config) {
+			cfg.MinVersion = version
+		})
+	}
+}
+
+// WithClientMinTLSVersion is used to set the minimum TLS version used to talk to the API, such
+// as tls.VersionTLS13. This only applies when the HTTP client uses a *http.Transport.
+func WithClientMinTLSVersion(version uint16) ClientOption {
+	return func(c *Client) {
+		c.client = withTLSConfig(c.client, func(cfg *tls.Config) {
+			cfg.MinVersion = version
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package sdk
+
+import "context"
+
+// Span is used to define a span started by a Tracer.
+type Span interface {
+	// End is used to end the span, recording the error if it is not nil.
+	End(err error)
+}
+
+// Tracer is used to trace scheduling, API requests, and job execution. The trace context is
+// carried inside the encrypted payload so that the execution of a job is linked to the span
+// it was scheduled in. See the tracing/oteladapter module for an OpenTelemetry implementation.
+type Tracer interface {
+	// Start is used to start a span with the name and attributes specified.
+	Start(ctx context.Context, name string, keysAndValues ...any) (context.Context, Span)
+
+	// Inject is used to write the trace context of ctx into the carrier.
+	Inject(ctx context.Context, carrier map[string]string)
+
+	// Extract is used to get a context with the trace context in the carrier.
+	Extract(ctx context.Context, carrier map[string]string) context.Context
+}
+
+type nopSpan struct{}
+
+func (nopSpan) End(error) {}
+
+type nopTracer struct{}
+
+func (nopTracer) Start(ctx context.Context, _ string, _ ...any) (context.Context, Span) {
+	return ctx, nopSpan{}
+}
+
+func (nopTracer) Inject(context.Context, map[string]string) {}
+
+func (nopTracer) Extract(ctx context.Context, _ map[string]string) context.Context {
+	return ctx
+}
+
+// WithTracer is used to set the tracer the server traces with. By default, nothing is traced.
+func WithTracer(t Tracer) ServerOption {
+	return func(s *Server) {
+		s.Client.tracer = tracerOrNop(t)
+	}
+}
+
+// WithClientTracer is used to set the tracer the client traces with. By default, nothing is
+// traced.
+func WithClientTracer(t Tracer) ClientOption {
+	return func(c *Client) {
+		c.tracer = tracerOrNop(t)
+	}
+}
+
+func tracerOrNop(t Tracer) Tracer {
+	if t == nil {
+		return nopTracer{}
+	}
+	return t
+}
+
+// Gets the trace context to carry in the payload of a job.
+func (c *Client) traceCarrier(ctx context.Context) map[string]string {
+	carrier := map[string]string{}
+	c.tracer.Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return nil
+	}
+	return carrier
+}
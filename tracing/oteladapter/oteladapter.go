@@ -0,0 +1,96 @@
+// Package oteladapter is used to trace the Clocktick SDK with OpenTelemetry.
+package oteladapter
+
+import (
+	"context"
+	"fmt"
+
+	"go.clocktick.dev/sdk"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "go.clocktick.dev/sdk"
+
+type span struct {
+	s trace.Span
+}
+
+func (s span) End(err error) {
+	if err != nil {
+		s.s.RecordError(err)
+		s.s.SetStatus(codes.Error, err.Error())
+	}
+	s.s.End()
+}
+
+type tracer struct {
+	t trace.Tracer
+	p propagation.TextMapPropagator
+}
+
+// Gets the kind of the span from its name.
+func spanKind(name string) trace.SpanKind {
+	switch name {
+	case "clocktick.schedule":
+		return trace.SpanKindProducer
+	case "clocktick.deliver":
+		return trace.SpanKindConsumer
+	case "clocktick.request":
+		return trace.SpanKindClient
+	}
+	return trace.SpanKindInternal
+}
+
+// Converts the key/value pairs passed by the SDK into attributes.
+func attributes(keysAndValues []any) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, len(keysAndValues)/2)
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		key := attribute.Key("clocktick." + fmt.Sprint(keysAndValues[i]))
+		switch v := keysAndValues[i+1].(type) {
+		case string:
+			attrs = append(attrs, key.String(v))
+		case int:
+			attrs = append(attrs, key.Int(v))
+		case bool:
+			attrs = append(attrs, key.Bool(v))
+		default:
+			attrs = append(attrs, key.String(fmt.Sprint(v)))
+		}
+	}
+	return attrs
+}
+
+func (t tracer) Start(ctx context.Context, name string, keysAndValues ...any) (context.Context, sdk.Span) {
+	ctx, s := t.t.Start(
+		ctx, name, trace.WithSpanKind(spanKind(name)),
+		trace.WithAttributes(attributes(keysAndValues)...),
+	)
+	return ctx, span{s: s}
+}
+
+func (t tracer) Inject(ctx context.Context, carrier map[string]string) {
+	t.p.Inject(ctx, propagation.MapCarrier(carrier))
+}
+
+func (t tracer) Extract(ctx context.Context, carrier map[string]string) context.Context {
+	if len(carrier) == 0 {
+		return ctx
+	}
+	return t.p.Extract(ctx, propagation.MapCarrier(carrier))
+}
+
+// New is used to create a sdk.Tracer which starts spans with the tracer provider and carries
+// the trace context with the propagator specified. If either is nil, the global one is used.
+func New(tp trace.TracerProvider, p propagation.TextMapPropagator) sdk.Tracer {
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	if p == nil {
+		p = otel.GetTextMapPropagator()
+	}
+	return tracer{t: tp.Tracer(instrumentationName), p: p}
+}
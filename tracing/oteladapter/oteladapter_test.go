@@ -0,0 +1,41 @@
+package oteladapter_test
+
+import (
+	"context"
+	"testing"
+
+	"go.clocktick.dev/sdk/tracing/oteladapter"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestCarriesTraceContext(t *testing.T) {
+	tr := oteladapter.New(noop.NewTracerProvider(), propagation.TraceContext{})
+	parent := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{2},
+		TraceFlags: trace.FlagsSampled,
+	})
+
+	ctx, span := tr.Start(trace.ContextWithSpanContext(context.Background(), parent), "clocktick.schedule")
+	defer span.End(nil)
+	carrier := map[string]string{}
+	tr.Inject(ctx, carrier)
+	if carrier["traceparent"] == "" {
+		t.Fatalf("nothing was injected: %v", carrier)
+	}
+
+	got := trace.SpanContextFromContext(tr.Extract(context.Background(), carrier))
+	if got.TraceID() != parent.TraceID() || !got.IsRemote() {
+		t.Errorf("extracted %v, want a remote span in trace %s", got, parent.TraceID())
+	}
+}
+
+func TestExtractWithoutCarrier(t *testing.T) {
+	tr := oteladapter.New(noop.NewTracerProvider(), propagation.TraceContext{})
+	ctx := context.Background()
+	if got := tr.Extract(ctx, nil); got != ctx {
+		t.Error("context was replaced without a carrier")
+	}
+}
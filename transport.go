@@ -0,0 +1,18 @@
+package sdk
+
+import (
+	"net/http"
+	"time"
+)
+
+// DefaultClient is used to create a HTTP client with production ready settings for talking
+// to the API. Unlike http.DefaultClient, it has timeouts set on every stage of the request.
+func DefaultClient() *http.Client {
+	return &http.Client{
+		Timeout:   60 * time.Second,
+		Transport: defaultTransport(),
+	}
+}
+
+// Used when no client is specified so that connections are shared between calls.
+var defaultClient = DefaultClient()
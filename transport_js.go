@@ -0,0 +1,20 @@
+//go:build js && wasm
+
+package sdk
+
+import (
+	"fmt"
+	"net/http"
+	"syscall/js"
+)
+
+// Creates the transport used by DefaultClient. Under js/wasm the transport only uses the
+// fetch API when no dial functions are set, and fetch ignores the transport timeouts, so the
+// timeout on the client is what applies.
+func defaultTransport() *http.Transport {
+	return &http.Transport{}
+}
+
+func defaultPanicHandler(err any) {
+	js.Global().Get("console").Call("error", fmt.Sprint("panic whilst running job: ", err))
+}
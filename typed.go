@@ -0,0 +1,173 @@
+package sdk
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// ArgumentError is returned when an argument of a delivery cannot be decoded into the type
+// the handler expects.
+type ArgumentError struct {
+	Index int
+	Err   error
+}
+
+// Error is used to convert the argument error to a string.
+func (e *ArgumentError) Error() string {
+	return "failed to decode argument " + strconv.Itoa(e.Index) + ": " + e.Err.Error()
+}
+
+// Unwrap is used to get the underlying decoding error.
+func (e *ArgumentError) Unwrap() error {
+	return e.Err
+}
+
+// Decodes the raw argument at the index into a value of type T. A missing argument decodes
+// to the zero value.
+func decodeArg[T any](raws []msgpack.RawMessage, index int) (v T, err error) {
+	if len(raws[index]) == 0 {
+		return v, nil
+	}
+	if err = msgpack.Unmarshal(raws[index], &v); err != nil {
+		err = &ArgumentError{Index: index, Err: err}
+	}
+	return
+}
+
+// Sets the function used to check the arguments of the version of the route decode.
+func (s *Server) setCheck(
+	route string, opts []Option, check func(raws []msgpack.RawMessage) error,
+) {
+	v := funcOpts{a: opts}.version()
+	r := s.routeVersions[route][v]
+	r.check = check
+	s.routeVersions[route][v] = r
+	if s.funcMap[route].version() == v {
+		s.funcMap[route] = r
+	}
+}
+
+// Route1 is a route with one argument added with AddRoute1.
+type Route1[T1 any] struct {
+	s     *Server
+	route string
+}
+
+// Schedule is used to schedule a job for the route.
+func (r Route1[T1]) Schedule(
+	ctx context.Context, props ScheduleJobPropertiesBuilder, a1 T1,
+) (JobCreationResponse, error) {
+	return r.s.ScheduleJob(ctx, r.route, props, a1)
+}
+
+// AddRoute1 is used to add a route with one argument to the server. Unlike AddRoute, the
+// argument types are checked at compile time and the arguments are decoded into them before
+// f is called. The returned route schedules jobs with the same types. Like AddRoute, this
+// panics if an argument type cannot be encoded.
+func AddRoute1[T1 any](
+	s *Server, route string, f func(context.Context, T1) error, opts ...Option,
+) Route1[T1] {
+	checkParam[T1](route, 0)
+	s.AddDispatcher(route, 1, func(ctx context.Context, raws []msgpack.RawMessage) error {
+		a1, err := decodeArg[T1](raws, 0)
+		if err != nil {
+			return err
+		}
+		return f(ctx, a1)
+	}, opts...)
+	s.setCheck(route, opts, func(raws []msgpack.RawMessage) error {
+		_, err := decodeArg[T1](raws, 0)
+		return err
+	})
+	return Route1[T1]{s: s, route: route}
+}
+
+// Route2 is a route with two arguments added with AddRoute2.
+type Route2[T1, T2 any] struct {
+	s     *Server
+	route string
+}
+
+// Schedule is used to schedule a job for the route.
+func (r Route2[T1, T2]) Schedule(
+	ctx context.Context, props ScheduleJobPropertiesBuilder, a1 T1, a2 T2,
+) (JobCreationResponse, error) {
+	return r.s.ScheduleJob(ctx, r.route, props, a1, a2)
+}
+
+// AddRoute2 is used to add a route with two arguments to the server. See AddRoute1.
+func AddRoute2[T1, T2 any](
+	s *Server, route string, f func(context.Context, T1, T2) error, opts ...Option,
+) Route2[T1, T2] {
+	checkParam[T1](route, 0)
+	checkParam[T2](route, 1)
+	s.AddDispatcher(route, 2, func(ctx context.Context, raws []msgpack.RawMessage) error {
+		a1, err := decodeArg[T1](raws, 0)
+		if err != nil {
+			return err
+		}
+		a2, err := decodeArg[T2](raws, 1)
+		if err != nil {
+			return err
+		}
+		return f(ctx, a1, a2)
+	}, opts...)
+	s.setCheck(route, opts, func(raws []msgpack.RawMessage) error {
+		if _, err := decodeArg[T1](raws, 0); err != nil {
+			return err
+		}
+		_, err := decodeArg[T2](raws, 1)
+		return err
+	})
+	return Route2[T1, T2]{s: s, route: route}
+}
+
+// Route3 is a route with three arguments added with AddRoute3.
+type Route3[T1, T2, T3 any] struct {
+	s     *Server
+	route string
+}
+
+// Schedule is used to schedule a job for the route.
+func (r Route3[T1, T2, T3]) Schedule(
+	ctx context.Context, props ScheduleJobPropertiesBuilder, a1 T1, a2 T2, a3 T3,
+) (JobCreationResponse, error) {
+	return r.s.ScheduleJob(ctx, r.route, props, a1, a2, a3)
+}
+
+// AddRoute3 is used to add a route with three arguments to the server. See AddRoute1.
+func AddRoute3[T1, T2, T3 any](
+	s *Server, route string, f func(context.Context, T1, T2, T3) error, opts ...Option,
+) Route3[T1, T2, T3] {
+	checkParam[T1](route, 0)
+	checkParam[T2](route, 1)
+	checkParam[T3](route, 2)
+	s.AddDispatcher(route, 3, func(ctx context.Context, raws []msgpack.RawMessage) error {
+		a1, err := decodeArg[T1](raws, 0)
+		if err != nil {
+			return err
+		}
+		a2, err := decodeArg[T2](raws, 1)
+		if err != nil {
+			return err
+		}
+		a3, err := decodeArg[T3](raws, 2)
+		if err != nil {
+			return err
+		}
+		return f(ctx, a1, a2, a3)
+	}, opts...)
+	s.setCheck(route, opts, func(raws []msgpack.RawMessage) error {
+		if _, err := decodeArg[T1](raws, 0); err != nil {
+			return err
+		}
+		if _, err := decodeArg[T2](raws, 1); err != nil {
+			return err
+		}
+		_, err := decodeArg[T3](raws, 2)
+		return err
+	})
+	return Route3[T1, T2, T3]{s: s, route: route}
+}
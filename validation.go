@@ -0,0 +1,161 @@
+package sdk
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// WithStrictScheduling is used to enable strict validation of schedules before they are sent
+// to the API. With this enabled, FromTime schedules in the past and FromNow schedules that
+// are not recurring and have a zero delta are rejected with a descriptive error.
+func WithStrictScheduling() ServerOption {
+	return func(s *Server) {
+		s.strict = true
+	}
+}
+
+// Checks if the delta is zero.
+func (d Delta) isZero() bool {
+	return d == Delta{}
+}
+
+// Validates the schedule, wrapping the reason it is rejected in a ValidationError.
+func validateProps(props ScheduleJobPropertiesBuilder, now time.Time, strict bool) error {
+	if err := props.validate(now, strict); err != nil {
+		return &ValidationError{Err: err}
+	}
+	return nil
+}
+
+func (p FromNowPropertiesBuilder) validate(now time.Time, strict bool) error {
+	if err := p.end.validate(p.recurring); err != nil {
+		return err
+	}
+	if err := p.exclusions.validate(); err != nil {
+		return err
+	}
+	if err := p.labels.validate(); err != nil {
+		return err
+	}
+	if err := p.conflict.validate(p.id); err != nil {
+		return err
+	}
+	if !strict {
+		return nil
+	}
+	if !p.recurring && p.d.isZero() {
+		return errors.New("FromNow schedule has a zero delta and is not recurring")
+	}
+	return nil
+}
+
+func (p FromTimePropertiesBuilder) validate(now time.Time, strict bool) error {
+	if err := p.end.validate(p.d != nil); err != nil {
+		return err
+	}
+	if err := p.exclusions.validate(); err != nil {
+		return err
+	}
+	if err := p.labels.validate(); err != nil {
+		return err
+	}
+	if err := p.conflict.validate(p.id); err != nil {
+		return err
+	}
+	if p.timezone != "" {
+		if p.timezone == "Local" {
+			return errors.New("the local timezone cannot be sent to the API, use a named location")
+		}
+		if _, err := time.LoadLocation(p.timezone); err != nil {
+			return fmt.Errorf("invalid timezone %q: %w", p.timezone, err)
+		}
+	}
+	if !strict {
+		return nil
+	}
+	if p.t.IsZero() {
+		return errors.New("FromTime schedule has no start time")
+	}
+	if p.t.Before(now) {
+		return fmt.Errorf(
+			"FromTime schedule starts at %s which is %s in the past",
+			p.t.UTC().Format(time.RFC3339), now.Sub(p.t).Truncate(time.Second),
+		)
+	}
+	return nil
+}
+
+// WithMinRecurringInterval is used to refuse to create recurring jobs which would run more
+// often than the interval specified. Months are treated as 28 days and years as 365 days
+// so that the shortest possible interval is checked. Cron expressions and RRULEs have no
+// fixed interval, so the shortest gap between their next runs is checked instead.
+func WithMinRecurringInterval(d time.Duration) ServerOption {
+	return func(s *Server) {
+		s.minRecurringInterval = d
+	}
+}
+
+// The number of upcoming runs checked for the shortest gap of a schedule without a fixed
+// interval.
+const intervalSampleRuns = 64
+
+// Gets the shortest duration the delta can represent.
+func (d Delta) minDuration() time.Duration {
+	days := time.Duration(d.Years)*365 + time.Duration(d.Months)*28 + time.Duration(d.Days)
+	return days*24*time.Hour +
+		time.Duration(d.Hours)*time.Hour +
+		time.Duration(d.Minutes)*time.Minute +
+		time.Duration(d.Seconds)*time.Second +
+		time.Duration(d.Milliseconds)*time.Millisecond
+}
+
+// Gets the shortest gap between the next runs of the schedule, returning false if it runs
+// fewer than twice.
+func shortestGap(props ScheduleJobPropertiesBuilder, now time.Time) (time.Duration, bool) {
+	prev, next, err := runTimes(props, now)
+	if err != nil || next == nil {
+		return 0, false
+	}
+	var gap time.Duration
+	found := false
+	for i := 0; i < intervalSampleRuns; i++ {
+		t, ok := next(prev)
+		if !ok {
+			break
+		}
+		if d := t.Sub(prev); !found || d < gap {
+			gap, found = d, true
+		}
+		prev = t
+	}
+	return gap, found
+}
+
+// Checks the recurring interval of the job against the configured minimum.
+func (s *Server) checkRecurringInterval(props ScheduleJobPropertiesBuilder, runEvery *Delta) error {
+	if s.minRecurringInterval == 0 {
+		return nil
+	}
+	var interval time.Duration
+	switch props.(type) {
+	case FromCronPropertiesBuilder, FromRRulePropertiesBuilder:
+		gap, ok := shortestGap(props, s.now())
+		if !ok {
+			return nil
+		}
+		interval = gap
+	default:
+		if runEvery == nil {
+			return nil
+		}
+		interval = runEvery.minDuration()
+	}
+	if interval < s.minRecurringInterval {
+		return &ValidationError{Err: fmt.Errorf(
+			"recurring interval %s is shorter than the minimum of %s",
+			interval, s.minRecurringInterval,
+		)}
+	}
+	return nil
+}
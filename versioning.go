@@ -0,0 +1,52 @@
+package sdk
+
+import "fmt"
+
+// RouteVersion is used to set the version of the route as an option, so that a route can be
+// added more than once when the arguments of its handler change. Jobs are scheduled against
+// the highest version, and each delivery runs the version its job was scheduled against, so
+// jobs scheduled before a deploy which changed the arguments still decode. Routes without a
+// version are version 0. Keep old versions added until all of their jobs have run. The
+// concurrency limit of the highest version applies to deliveries of every version.
+func RouteVersion(version uint) Option {
+	return Option{version: &version}
+}
+
+// Gets the version of the route.
+func (f funcOpts) version() uint {
+	var v uint
+	for _, opt := range f.a {
+		if opt.version != nil {
+			v = *opt.version
+		}
+	}
+	return v
+}
+
+// Adds the version of the route, making it the one jobs are scheduled against if it is the
+// highest. Returns an error if the version has already been added.
+func (s *Server) addRoute(route string, r funcOpts) error {
+	v := r.version()
+	versions := s.routeVersions[route]
+	if _, ok := versions[v]; ok {
+		if v == 0 {
+			return fmt.Errorf("route %q has already been added", route)
+		}
+		return fmt.Errorf("version %d of route %q has already been added", v, route)
+	}
+	if versions == nil {
+		versions = make(map[uint]funcOpts)
+		s.routeVersions[route] = versions
+	}
+	versions[v] = r
+	if latest, ok := s.funcMap[route]; !ok || v > latest.version() {
+		s.funcMap[route] = r
+	}
+	return nil
+}
+
+// Gets the version of the route which jobs scheduled against that version run with.
+func (s *Server) routeVersion(route string, version uint) (funcOpts, bool) {
+	r, ok := s.routeVersions[route][version]
+	return r, ok
+}
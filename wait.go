@@ -0,0 +1,84 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const (
+	minWaitInterval = 250 * time.Millisecond
+	maxWaitInterval = 10 * time.Second
+)
+
+// JobOutcome defines how a job ended, returned by WaitForJob.
+type JobOutcome struct {
+	// Job is the job in its final state. Its Status is either JobStatusCompleted or
+	// JobStatusFailed.
+	Job Job
+
+	// Result is the JSON encoded value the handler returned if the job completed and the
+	// handler returned one.
+	Result json.RawMessage
+}
+
+// Checks if the job will never reach a terminal state.
+func (j Job) recurring() bool {
+	return j.RunEvery != nil || j.StartFrom.Type == "cron" || j.StartFrom.Type == "rrule"
+}
+
+// Gets how long to wait before polling the job again. The wait grows after each poll but is
+// cut short when the job is due to run sooner.
+func waitInterval(job Job, polls int, now time.Time) time.Duration {
+	d := maxWaitInterval
+	if polls < 10 && minWaitInterval<<polls < d {
+		d = minWaitInterval << polls
+	}
+	if job.NextRunAt != nil {
+		if untilRun := job.NextRunAt.Sub(now); untilRun > minWaitInterval && untilRun < d {
+			d = untilRun
+		}
+	}
+	return d
+}
+
+// WaitForJob is used to poll the API until the job with the ID specified has completed or
+// failed, so that scripts which schedule a job for the near future can wait for it. The
+// result of the handler is fetched when the job completes. Recurring jobs never finish, so an
+// error is returned for them. Use the context to give up waiting.
+func (c *Client) WaitForJob(ctx context.Context, jobId string) (JobOutcome, error) {
+	for polls := 0; ; polls++ {
+		job, err := c.GetJob(ctx, jobId)
+		if err != nil {
+			return JobOutcome{}, err
+		}
+		switch {
+		case job.Status == JobStatusFailed:
+			return JobOutcome{Job: job}, nil
+		case job.Status == JobStatusCompleted:
+			outcome := JobOutcome{Job: job}
+			res, err := c.GetJobResult(ctx, jobId)
+			var apiErr APIError
+			if errors.As(err, &apiErr) && apiErr.Type == "not_found" {
+				// The handler did not return a result.
+				return outcome, nil
+			}
+			if err != nil {
+				return outcome, err
+			}
+			outcome.Result = res.Result
+			return outcome, nil
+		case job.recurring():
+			return JobOutcome{}, errors.New("job is recurring so it never finishes")
+		}
+
+		t := time.NewTimer(waitInterval(job, polls, time.Now()))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return JobOutcome{}, ctx.Err()
+		case <-t.C:
+		}
+	}
+}
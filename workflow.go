@@ -0,0 +1,158 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Defines a job to schedule once the job carrying it in its payload succeeds.
+type workflowStep struct {
+	Route    string               `msgpack:"r"`
+	ID       string               `msgpack:"i,omitempty"`
+	Skeleton []byte               `msgpack:"s"`
+	Args     []msgpack.RawMessage `msgpack:"a"`
+
+	// PerRun is set when the job before the step is recurring, so the step is scheduled
+	// after every run rather than once.
+	PerRun bool `msgpack:"p,omitempty"`
+
+	// Version is the version of the route the arguments were encoded for.
+	Version uint `msgpack:"v,omitempty"`
+}
+
+// Checks if the job the skeleton is for runs more than once.
+func (body createJobSkeleton) recurring() bool {
+	switch body.StartFrom.(type) {
+	case startFromCron, startFromRRule:
+		return true
+	}
+	return body.RunEvery != nil
+}
+
+// Validates and encodes a step which runs after the first step of a workflow.
+func (s *Server) buildWorkflowStep(spec JobSpec) (workflowStep, error) {
+	r, ok := s.funcMap[spec.Route]
+	if !ok {
+		return workflowStep{}, fmt.Errorf("%w: %s", ErrRouteNotFound, spec.Route)
+	}
+	if err := validateProps(spec.Props, s.now(), s.strict); err != nil {
+		return workflowStep{}, err
+	}
+	if r.numArgs != len(spec.Args) {
+		return workflowStep{}, argumentMismatch(r.numArgs, len(spec.Args))
+	}
+	raws, err := encodeArgs(spec.Args)
+	if err != nil {
+		return workflowStep{}, err
+	}
+	if err = r.checkArgs(raws); err != nil {
+		return workflowStep{}, err
+	}
+	id, body := spec.Props.buildSkeleton()
+	if body.recurring() {
+		return workflowStep{}, errors.New("only the first step of a workflow can be recurring")
+	}
+	skeleton, err := json.Marshal(body)
+	if err != nil {
+		return workflowStep{}, err
+	}
+	return workflowStep{
+		Route: spec.Route, ID: id, Skeleton: skeleton, Args: raws, Version: r.version(),
+	}, nil
+}
+
+// ScheduleWorkflow is used to schedule a chain of jobs where each job is only scheduled once
+// the one before it succeeds, such as an export followed by a compress followed by an upload.
+// The schedule of each step is relative to when the step before it finishes. The steps are
+// checked up front and carried in the encrypted payload of the first job, so nothing needs to
+// be stored. The first step can be recurring, in which case the rest of the steps follow
+// each of its runs and cannot have a custom ID. If the next step cannot be scheduled, the delivery fails so the step is
+// re-delivered, so handlers in a workflow should be safe to run more than once. The
+// response is for the first job.
+func (s *Server) ScheduleWorkflow(
+	ctx context.Context, steps ...JobSpec,
+) (JobCreationResponse, error) {
+	if len(steps) == 0 {
+		return JobCreationResponse{}, errors.New("workflow has no steps")
+	}
+	next := make([]workflowStep, len(steps)-1)
+	for i, spec := range steps[1:] {
+		step, err := s.buildWorkflowStep(spec)
+		if err != nil {
+			return JobCreationResponse{}, fmt.Errorf("step %d: %w", i+2, err)
+		}
+		next[i] = step
+	}
+	if _, first := steps[0].Props.buildSkeleton(); first.recurring() && len(next) != 0 {
+		// Every run schedules the rest of the steps again, so a custom ID would conflict with
+		// the step scheduled by the run before.
+		for i, step := range next {
+			if step.ID != "" {
+				return JobCreationResponse{}, fmt.Errorf(
+					"step %d: steps after a recurring step cannot have a custom ID", i+2,
+				)
+			}
+		}
+		next[0].PerRun = true
+	}
+	if s.local != nil {
+		return s.scheduleLocal(ctx, steps[0].Route, steps[0].Props, steps[0].Args, steps[1:])
+	}
+
+	ctx, span := s.tracer.Start(ctx, "clocktick.schedule", "route", steps[0].Route)
+	id, body, err := s.prepareJob(ctx, steps[0].Route, steps[0].Props, steps[0].Args, next)
+	if err != nil {
+		span.End(err)
+		return JobCreationResponse{}, err
+	}
+	s.incr(counterScheduleCalls)
+	res, err := s.submitJob(ctx, id, body)
+	span.End(err)
+	return res, err
+}
+
+// Schedules the next step of a workflow after the job succeeded, carrying the rest of the
+// steps along with it.
+func (s *Server) continueWorkflow(ctx context.Context, jobID string, next []workflowStep) error {
+	step := next[0]
+	// The step is sent to the endpoint of the highest version if its own version has gone,
+	// since the delivery can still be migrated.
+	r, ok := s.routeVersion(step.Route, step.Version)
+	if !ok {
+		r, ok = s.funcMap[step.Route]
+	}
+	if !ok {
+		return fmt.Errorf("route %q of the next step not found", step.Route)
+	}
+	var body createJobSkeleton
+	if err := json.Unmarshal(step.Skeleton, &body); err != nil {
+		return err
+	}
+	b, err := s.packPayload(ctx, payloadEnvelope{
+		Args: step.Args, Baggage: s.collectBaggage(ctx), Trace: s.traceCarrier(ctx),
+		Next: next[1:], Version: step.Version,
+	})
+	if err != nil {
+		return err
+	}
+	if body, err = s.sealJob(ctx, body, step.Route, s.routeEndpoint(r), b); err != nil {
+		return err
+	}
+
+	// Key the request on the job so that a re-delivery does not schedule the step twice. Steps
+	// after a recurring job are keyed on the run as well.
+	if step.ID == "" {
+		key := "workflow-" + jobID + "-" + strconv.Itoa(len(next))
+		if jc, ok := JobFromContext(ctx); ok && step.PerRun {
+			key += "-" + strconv.FormatInt(jc.ScheduledAt.UnixMilli(), 10)
+		}
+		body.idempotencyKey = key
+	}
+	_, err = s.submitJob(ctx, step.ID, body)
+	return err
+}
@@ -0,0 +1,139 @@
+package sdk_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"go.clocktick.dev/sdk"
+	"go.clocktick.dev/sdk/sdktest"
+)
+
+// Delivers the job created by the request to the server, as the service would when it is due.
+func deliverCreated(
+	t *testing.T, s *sdk.Server, signer *sdktest.Signer, created recordedRequest, jobID string,
+) int {
+	t.Helper()
+	job := created.json(t)
+	body, err := json.Marshal(map[string]any{
+		"type": job["job_type"], "job_id": jobID, "encrypted_data": job["encrypted_data"],
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := signer.Sign("/", body, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return serve(s, req).Code
+}
+
+func TestWorkflowSchedulesNextStep(t *testing.T) {
+	api := newRecordingAPI(t)
+	s, signer := newTestServer(t, api.URL)
+	_, exports := addRecordingRoute(s, "export", nil)
+	_, uploads := addRecordingRoute(s, "upload", nil)
+	ctx := context.Background()
+
+	_, err := s.ScheduleWorkflow(ctx,
+		sdk.JobSpec{Route: "export", Props: sdk.FromNow().Minutes(1), Args: []any{"report"}},
+		sdk.JobSpec{Route: "upload", Props: sdk.FromNow().Seconds(30), Args: []any{"bucket"}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reqs := api.received()
+	if len(reqs) != 1 {
+		t.Fatalf("API received %d requests, want only the first step", len(reqs))
+	}
+
+	// Running the first step schedules the second, keyed on the job so a re-delivery does not
+	// schedule it twice.
+	if code := deliverCreated(t, s, signer, reqs[0], "job_1"); code != http.StatusOK {
+		t.Fatalf("first step delivery status = %d", code)
+	}
+	if got := exports(); len(got) != 1 || got[0] != "report" {
+		t.Errorf("export got %v, want [report]", got)
+	}
+	reqs = api.received()
+	if len(reqs) != 2 {
+		t.Fatalf("API received %d requests, want 2", len(reqs))
+	}
+	next := reqs[1]
+	if got := next.json(t)["job_type"]; got != "upload" {
+		t.Errorf("next step route = %v, want upload", got)
+	}
+	key := next.Header.Get("Idempotency-Key")
+	if !strings.HasPrefix(key, "workflow-job_1-") {
+		t.Errorf("Idempotency-Key = %q, want it keyed on job_1", key)
+	}
+
+	// The second step carries no further steps.
+	if code := deliverCreated(t, s, signer, next, "job_2"); code != http.StatusOK {
+		t.Fatalf("second step delivery status = %d", code)
+	}
+	if got := uploads(); len(got) != 1 || got[0] != "bucket" {
+		t.Errorf("upload got %v, want [bucket]", got)
+	}
+	if n := len(api.received()); n != 2 {
+		t.Errorf("API received %d requests after the last step, want 2", n)
+	}
+}
+
+func TestWorkflowFailsDeliveryWhenNextStepCannotBeScheduled(t *testing.T) {
+	api := newRecordingAPI(t)
+	s, signer := newTestServer(t, api.URL, sdk.WithRetryPolicy(sdk.RetryPolicy{MaxAttempts: 1}))
+	addRecordingRoute(s, "export", nil)
+	addRecordingRoute(s, "upload", nil)
+
+	_, err := s.ScheduleWorkflow(context.Background(),
+		sdk.JobSpec{Route: "export", Props: sdk.FromNow().Minutes(1), Args: []any{"report"}},
+		sdk.JobSpec{Route: "upload", Props: sdk.FromNow().Seconds(30), Args: []any{"bucket"}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	api.setReply(func(recordedRequest) (int, string) { return http.StatusServiceUnavailable, `{}` })
+	code := deliverCreated(t, s, signer, api.received()[0], "job_1")
+	if code < 500 {
+		t.Errorf("delivery status = %d, want a retryable failure", code)
+	}
+}
+
+func TestWorkflowRejectsCustomIDsAfterRecurringStep(t *testing.T) {
+	api := newRecordingAPI(t)
+	s, _ := newTestServer(t, api.URL)
+	addRecordingRoute(s, "export", nil)
+	addRecordingRoute(s, "upload", nil)
+	addRecordingRoute(s, "notify", nil)
+
+	_, err := s.ScheduleWorkflow(context.Background(),
+		sdk.JobSpec{Route: "export", Props: sdk.FromNow().Days(1).Recurring(), Args: []any{"a"}},
+		sdk.JobSpec{Route: "upload", Props: sdk.FromNow().Seconds(30), Args: []any{"b"}},
+		sdk.JobSpec{Route: "notify", Props: sdk.FromNow().Seconds(30).CustomID("notify"), Args: []any{"c"}},
+	)
+	if err == nil || !strings.Contains(err.Error(), "step 3") {
+		t.Errorf("err = %v, want a custom ID error for step 3", err)
+	}
+	if n := len(api.received()); n != 0 {
+		t.Errorf("API received %d requests, want 0", n)
+	}
+}
+
+func TestWorkflowRejectsRecurringLaterStep(t *testing.T) {
+	api := newRecordingAPI(t)
+	s, _ := newTestServer(t, api.URL)
+	addRecordingRoute(s, "export", nil)
+	addRecordingRoute(s, "upload", nil)
+
+	_, err := s.ScheduleWorkflow(context.Background(),
+		sdk.JobSpec{Route: "export", Props: sdk.FromNow().Minutes(1), Args: []any{"a"}},
+		sdk.JobSpec{Route: "upload", Props: sdk.FromNow().Hours(1).Recurring(), Args: []any{"b"}},
+	)
+	if err == nil {
+		t.Error("recurring second step was accepted")
+	}
+}